@@ -0,0 +1,56 @@
+package descrypt
+
+import "bytes"
+
+// AppendDESCrypt appends the 13-character DES crypt(3) hash of password
+// under salt to dst and returns the extended buffer. It performs no heap
+// allocations when dst has room for the hash. If salt contains a character
+// outside the crypt(3) alphabet, or the method policy disables "des", dst
+// is returned unchanged, so callers must check that it grew by 13 bytes.
+func AppendDESCrypt(dst []byte, password []byte, salt [2]byte) []byte {
+	s, ok := saltValue(salt)
+	if !ok || methodDisabled("des") {
+		return dst
+	}
+	var out [13]byte
+	desCrypt(&out, desKey(password), salt, saltMask(s, 12))
+	return append(dst, out[:]...)
+}
+
+// VerifyDESCrypt reports whether password matches a traditional DES crypt
+// hash (13 chars, optionally prefixed with "{CRYPT}"). Unlike
+// DESPasswordVerify it does not say why a check failed, and it performs no
+// heap allocations. It is constant-time in the same way as
+// DESPasswordVerify.
+func VerifyDESCrypt(password, hash []byte) bool {
+	var h Hasher
+	h.ks.init(desKey(password))
+	_, err := verifyDES(&h, hash)
+	return err == nil
+}
+
+// saltValue decodes a 2-character salt without allocating an error.
+func saltValue(salt [2]byte) (uint32, bool) {
+	v0 := bytes.IndexByte([]byte(itoa64), salt[0])
+	v1 := bytes.IndexByte([]byte(itoa64), salt[1])
+	if v0 < 0 || v1 < 0 {
+		return 0, false
+	}
+	return uint32(v0) | uint32(v1)<<6, true
+}
+
+// desCrypt writes the DES crypt(3) hash of key to out, under the salt with
+// characters salt and E-box swap mask mask.
+func desCrypt(out *[13]byte, key uint64, salt [2]byte, mask uint32) {
+	var ks keySchedule
+	ks.init(key)
+	desCryptSchedule(out, &ks, salt, mask)
+}
+
+// desCryptSchedule is desCrypt for a key whose schedule is already set up.
+func desCryptSchedule(out *[13]byte, ks *keySchedule, salt [2]byte, mask uint32) {
+	l, r := ks.encrypt(0, 0, mask, 25)
+	out[0] = salt[0]
+	out[1] = salt[1]
+	encodeBlock(out[2:], finalPermute(l, r))
+}
@@ -0,0 +1,74 @@
+package descrypt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendDESCrypt(t *testing.T) {
+	testCases := []struct {
+		password string
+		salt     string
+		expected string
+	}{
+		{"SecretPassword123", "rq", "rq/N3gSWdwWeA"},
+		{"TestPassword123", "pn", "pnA3klLBJ.CRU"},
+		{"", "xy", "xyw1.V0rbu5mQ"},
+		{"short", "12", "128Q9Am4iRrT6"},
+	}
+
+	for _, tc := range testCases {
+		dst := []byte("prefix:")
+		got := AppendDESCrypt(dst, []byte(tc.password), [2]byte{tc.salt[0], tc.salt[1]})
+		if string(got) != "prefix:"+tc.expected {
+			t.Errorf("AppendDESCrypt() = %q, want %q", got, "prefix:"+tc.expected)
+		}
+		if !VerifyDESCrypt([]byte(tc.password), []byte(tc.expected)) {
+			t.Errorf("VerifyDESCrypt() failed for password '%s' and hash '%s'", tc.password, tc.expected)
+		}
+		if !VerifyDESCrypt([]byte(tc.password), []byte("{CRYPT}"+tc.expected)) {
+			t.Errorf("VerifyDESCrypt() failed for {CRYPT} hash '%s'", tc.expected)
+		}
+		if VerifyDESCrypt([]byte("x"+tc.password), []byte(tc.expected)) {
+			t.Errorf("VerifyDESCrypt() should fail for wrong password")
+		}
+	}
+
+	if got := AppendDESCrypt([]byte("x"), []byte("pw"), [2]byte{'a', '!'}); !bytes.Equal(got, []byte("x")) {
+		t.Errorf("AppendDESCrypt() with invalid salt = %q, want dst unchanged", got)
+	}
+	for _, hash := range []string{"", "ab1xQWzQ9Qf", "ab1xQWzQ9Qf8wX", "a!w1.V0rbu5mQ"} {
+		if VerifyDESCrypt([]byte("password"), []byte(hash)) {
+			t.Errorf("VerifyDESCrypt() should fail with malformed hash '%s'", hash)
+		}
+	}
+}
+
+func TestAppendDESCryptAllocs(t *testing.T) {
+	password := []byte("SecretPassword123")
+	good := []byte("rq/N3gSWdwWeA")
+	bad := []byte("rqnO5.MEhjGLo")
+	buf := make([]byte, 0, 64)
+
+	if n := testing.AllocsPerRun(100, func() {
+		buf = AppendDESCrypt(buf[:0], password, [2]byte{'r', 'q'})
+	}); n != 0 {
+		t.Errorf("AppendDESCrypt() allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() {
+		VerifyDESCrypt(password, good)
+		VerifyDESCrypt(password, bad)
+		VerifyDESCrypt(password, good[:5])
+	}); n != 0 {
+		t.Errorf("VerifyDESCrypt() allocs = %v, want 0", n)
+	}
+}
+
+func BenchmarkVerifyDESCrypt(b *testing.B) {
+	password := []byte("SecretPassword123")
+	hash := []byte("rq/N3gSWdwWeA")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		VerifyDESCrypt(password, hash)
+	}
+}
@@ -0,0 +1,17 @@
+package descrypt
+
+// DESCryptHashBatch computes the DES crypt(3) hashes of many passwords under
+// one salt (2 chars). It runs the bitsliced engine, hashing up to 256
+// passwords per pass (using SSE2 or AVX2 where available), and returns the
+// hashes in the order of passwords; each is identical to what DESCryptHash
+// would return. It returns ErrMethodDisabled if the method policy disables
+// "des".
+func DESCryptHashBatch(passwords []string, salt string) ([]string, error) {
+	ctx, err := SaltContextFor(salt)
+	if err != nil {
+		return nil, err
+	}
+	// SaltContextFor has checked the policy; checking again in HashBatch
+	// could turn a policy change in between into a nil result.
+	return ctx.hashBatch(passwords), nil
+}
@@ -0,0 +1,61 @@
+package descrypt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDESCryptHashBatch(t *testing.T) {
+	passwords := []string{"SecretPassword123", "TestPassword123", "", "\xff\x80abc"}
+	for i := 0; i < 600; i++ {
+		passwords = append(passwords, fmt.Sprintf("pw%d-%x", i, i*7919))
+	}
+
+	for _, salt := range []string{"rq", "..", "zz", "A9"} {
+		hashes, err := DESCryptHashBatch(passwords, salt)
+		if err != nil {
+			t.Fatalf("DESCryptHashBatch() error = %v", err)
+		}
+		if len(hashes) != len(passwords) {
+			t.Fatalf("DESCryptHashBatch() returned %d hashes, want %d", len(hashes), len(passwords))
+		}
+		for i, pw := range passwords {
+			want, _ := DESCryptHash(pw, salt)
+			if hashes[i] != want {
+				t.Errorf("DESCryptHashBatch()[%d] = %v, want %v for password '%s' and salt '%s'", i, hashes[i], want, pw, salt)
+			}
+		}
+	}
+}
+
+func TestDESCryptHashBatchErrors(t *testing.T) {
+	if _, err := DESCryptHashBatch([]string{"a"}, "a"); err == nil {
+		t.Errorf("DESCryptHashBatch() should fail with short salt")
+	}
+	if _, err := DESCryptHashBatch([]string{"a"}, "a!"); err == nil {
+		t.Errorf("DESCryptHashBatch() should fail with invalid salt")
+	}
+	hashes, err := DESCryptHashBatch(nil, "ab")
+	if err != nil || len(hashes) != 0 {
+		t.Errorf("DESCryptHashBatch(nil) = %v, %v, want no hashes", hashes, err)
+	}
+}
+
+func BenchmarkDESCryptHashBatch(b *testing.B) {
+	passwords := make([]string, bsLanes)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("password%d", i)
+	}
+	defer func(round func(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)) {
+		bsRound = round
+	}(bsRound)
+
+	for _, be := range bsBackends {
+		b.Run(be.name, func(b *testing.B) {
+			bsRound = be.round
+			for i := 0; i < b.N; i++ {
+				DESCryptHashBatch(passwords, "rq")
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package descrypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+//go:generate go run gen_blowfish.go
+
+// bcrypt, Provos and Mazières' Blowfish-based scheme as implemented by
+// OpenBSD and Openwall's crypt_blowfish: "$2b$", a two-digit cost, "$", a
+// 22-character salt and a 31-character hash. The encoding uses the same
+// characters as itoa64 but in the order of bcryptAlphabet, and packs bytes
+// big-endian first. The password and a terminating NUL are cycled over the
+// 72-byte Blowfish key, so characters after the 72nd are ignored.
+//
+// "$2y$" and "$2b$" are the same algorithm. "$2a$" differs only for the
+// rare passwords with 8-bit characters that the "$2x$" sign-extension bug
+// happened not to affect; crypt_blowfish flips a bit of the first subkey
+// for those so that such "$2a$" hashes cannot be reused with "$2x$".
+
+const (
+	bcryptSettingLen = 7 + 22
+	bcryptHashLen    = 31
+	bcryptMinCost    = 4
+	bcryptMaxCost    = 31
+)
+
+// bcryptAlphabet is bcrypt's base64 alphabet.
+const bcryptAlphabet = "./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// BcryptHash computes the bcrypt hash for a password and a setting ("$2a$", "$2b$" or
+// "$2y$" + 2-digit cost + "$" + 22-char salt); anything after the setting is ignored
+// Returns a 60-character string (29-char setting + 31-char hash)
+func BcryptHash(password, setting string) (string, error) {
+	if err := checkMethod("bcrypt"); err != nil {
+		return "", err
+	}
+	variant, cost, salt, err := parseBcryptSetting(setting)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, 0, bcryptSettingLen+bcryptHashLen)
+	out = append(out, setting[:7]...)
+	out = appendBcrypt64(out, salt[:])
+	return string(bcrypt(out, password, variant, cost, &salt)), nil
+}
+
+// BcryptPasswordVerify verifies a password against a bcrypt hash ("$2b$cost$salthash", 60 chars)
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a bcrypt hash.
+// A "{CRYPT}" prefix is accepted. The final comparison is constant-time.
+func BcryptPasswordVerify(inputPassword string, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	variant, cost, salt, err := parseBcryptSetting(hash)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	if pe = checkEncoded(hash, bcryptSettingLen, bcryptSettingLen+bcryptHashLen); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	got := make([]byte, 0, len(hash))
+	got = append(got, hash[:7]...)
+	got = appendBcrypt64(got, salt[:])
+	// The last salt character carries 4 unused bits, which hashing
+	// clears; a hash with any of them set cannot have been produced.
+	if got[bcryptSettingLen-1] != hash[bcryptSettingLen-1] {
+		return &ParseError{Pos: offset + bcryptSettingLen - 1, Char: hash[bcryptSettingLen-1], Err: ErrMalformedHash}
+	}
+	got = bcrypt(got, inputPassword, variant, cost, &salt)
+	if subtle.ConstantTimeCompare(got, []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseBcryptSetting returns the variant letter, cost and 16-byte salt of
+// a bcrypt setting.
+func parseBcryptSetting(setting string) (variant byte, cost int, salt [16]byte, err error) {
+	const format = "$2?$00$"
+	for i := 0; i < len(format); i++ {
+		if i >= len(setting) {
+			return 0, 0, salt, &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		c := setting[i]
+		switch format[i] {
+		case '?':
+			if c != 'a' && c != 'b' && c != 'y' {
+				return 0, 0, salt, &ParseError{Pos: i, Char: c, Err: ErrInvalidSalt}
+			}
+		case '0':
+			if c < '0' || c > '9' {
+				return 0, 0, salt, &ParseError{Pos: i, Char: c, Err: ErrInvalidCost}
+			}
+		default:
+			if c != format[i] {
+				return 0, 0, salt, &ParseError{Pos: i, Char: c, Err: ErrInvalidSalt}
+			}
+		}
+	}
+	cost = int(setting[4]-'0')*10 + int(setting[5]-'0')
+	if cost < bcryptMinCost || cost > bcryptMaxCost {
+		return 0, 0, salt, &ParseError{Pos: 4, Char: setting[4], Err: ErrInvalidCost}
+	}
+
+	var acc uint32
+	for i := 7; i < bcryptSettingLen; i++ {
+		if i >= len(setting) {
+			return 0, 0, salt, &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		v := strings.IndexByte(bcryptAlphabet, setting[i])
+		if v < 0 {
+			return 0, 0, salt, &ParseError{Pos: i, Char: setting[i], Err: ErrInvalidSalt}
+		}
+		acc = acc<<6 | uint32(v)
+		if k := i - 7; k%4 == 3 {
+			salt[k/4*3], salt[k/4*3+1], salt[k/4*3+2] = byte(acc>>16), byte(acc>>8), byte(acc)
+		}
+	}
+	// The last two characters hold the final byte in their top 8 bits.
+	salt[15] = byte(acc >> 4)
+	return setting[2], cost, salt, nil
+}
+
+// appendBcrypt64 appends the bcrypt base64 encoding of src to dst: each 3
+// bytes, big-endian, become 4 characters, and a trailing 1 or 2 bytes
+// become 2 or 3.
+func appendBcrypt64(dst, src []byte) []byte {
+	for len(src) > 0 {
+		var v uint32
+		n := min(len(src), 3)
+		for i := 0; i < 3; i++ {
+			v <<= 8
+			if i < n {
+				v |= uint32(src[i])
+			}
+		}
+		for i := 0; i <= n; i++ {
+			dst = append(dst, bcryptAlphabet[v>>(18-6*i)&63])
+		}
+		src = src[n:]
+	}
+	return dst
+}
+
+// blowfish is a Blowfish key schedule.
+type blowfish struct {
+	p [18]uint32
+	s [4][256]uint32
+}
+
+func (b *blowfish) f(x uint32) uint32 {
+	return (b.s[0][x>>24] + b.s[1][x>>16&0xff]) ^ b.s[2][x>>8&0xff] + b.s[3][x&0xff]
+}
+
+// encrypt enciphers the block l, r.
+func (b *blowfish) encrypt(l, r uint32) (uint32, uint32) {
+	for i := 0; i < 16; i += 2 {
+		l ^= b.p[i]
+		r ^= b.f(l)
+		r ^= b.p[i+1]
+		l ^= b.f(r)
+	}
+	return r ^ b.p[17], l ^ b.p[16]
+}
+
+// expand XORs key into the subkeys and then replaces the subkeys and
+// S-boxes, in order, with the chained encryption of the zero block, XORing
+// in successive pairs of salt words if salt is not nil.
+func (b *blowfish) expand(key *[18]uint32, salt *[4]uint32) {
+	for i := range b.p {
+		b.p[i] ^= key[i]
+	}
+	var l, r uint32
+	k := 0
+	next := func() (uint32, uint32) {
+		if salt != nil {
+			l ^= salt[k]
+			r ^= salt[k+1]
+			k ^= 2
+		}
+		return b.encrypt(l, r)
+	}
+	for i := 0; i < len(b.p); i += 2 {
+		l, r = next()
+		b.p[i], b.p[i+1] = l, r
+	}
+	for s := range b.s {
+		for i := 0; i < len(b.s[s]); i += 2 {
+			l, r = next()
+			b.s[s][i], b.s[s][i+1] = l, r
+		}
+	}
+}
+
+// bcryptKey returns the Blowfish key for password: the password and a
+// terminating NUL, repeated to fill 72 bytes. It also reports whether the
+// "$2a$" countermeasure applies: the password has an 8-bit character after
+// the first of some 4-byte word, yet sign-extending them as "$2x$" does
+// would not have changed the key.
+func bcryptKey(password string) (key [18]uint32, flip bool) {
+	var sign, diff uint32
+	j := 0
+	for i := range key {
+		var bug uint32
+		for n := 0; n < 4; n++ {
+			var c byte
+			if j < len(password) {
+				c = password[j]
+				j++
+			} else {
+				j = 0
+			}
+			key[i] = key[i]<<8 | uint32(c)
+			bug = bug<<8 | uint32(int32(int8(c)))
+			if n > 0 {
+				sign |= bug & 0x80
+			}
+		}
+		diff |= key[i] ^ bug
+	}
+	return key, sign != 0 && diff == 0
+}
+
+// bcrypt appends the 31-character bcrypt hash of password under variant,
+// cost and salt to dst.
+func bcrypt(dst []byte, password string, variant byte, cost int, salt *[16]byte) []byte {
+	key, flip := bcryptKey(password)
+	var saltKey [18]uint32
+	var saltWords [4]uint32
+	for i := range saltWords {
+		saltWords[i] = uint32(salt[4*i])<<24 | uint32(salt[4*i+1])<<16 | uint32(salt[4*i+2])<<8 | uint32(salt[4*i+3])
+	}
+	for i := range saltKey {
+		saltKey[i] = saltWords[i%4]
+	}
+
+	b := &blowfish{p: blowfishP, s: blowfishS}
+	if variant == 'a' && flip {
+		b.p[0] ^= 0x10000
+	}
+	b.expand(&key, &saltWords)
+	for n := 1 << cost; n > 0; n-- {
+		b.expand(&key, nil)
+		b.expand(&saltKey, nil)
+	}
+
+	ctext := [6]uint32{0x4f727068, 0x65616e42, 0x65686f6c, 0x64657253, 0x63727944, 0x6f756274} // "OrpheanBeholderScryDoubt"
+	for i := 0; i < 64; i++ {
+		for j := 0; j < len(ctext); j += 2 {
+			ctext[j], ctext[j+1] = b.encrypt(ctext[j], ctext[j+1])
+		}
+	}
+	var sum [24]byte
+	for i, w := range ctext {
+		sum[4*i], sum[4*i+1], sum[4*i+2], sum[4*i+3] = byte(w>>24), byte(w>>16), byte(w>>8), byte(w)
+	}
+	return appendBcrypt64(dst, sum[:23])
+}
@@ -0,0 +1,120 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// bcrypt vectors from Openwall's crypt_blowfish, checked against libxcrypt.
+var bcryptTests = []struct {
+	password string
+	hash     string
+}{
+	{"U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+	{"U*U*", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.VGOzA784oUp/Z0DY336zx7pLYAy0lwK"},
+	{"U*U*U", "$2a$05$XXXXXXXXXXXXXXXXXXXXXOAcXxm9kjPGEMsLznoKqmqw7tc8WCx4a"},
+	{"0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789chars after 72 are ignored", "$2a$05$abcdefghijklmnopqrstuu5s2v8.iXieOjg/.AySBTTZIIVFJeBui"},
+	{"", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.7uG0VCzI2bS7j6ymqJi9CdcdxiRTWNy"},
+	{"\xa3", "$2a$05$/OK.fbVrR/bpIqNJ5ianF.Sa7shbm4.OzKpvFnX1pQLmQW96oUlCq"},
+	{"\xa3", "$2b$05$/OK.fbVrR/bpIqNJ5ianF.Sa7shbm4.OzKpvFnX1pQLmQW96oUlCq"},
+	{"\xa3", "$2y$05$/OK.fbVrR/bpIqNJ5ianF.Sa7shbm4.OzKpvFnX1pQLmQW96oUlCq"},
+	{"\xff\xa3345", "$2a$05$/OK.fbVrR/bpIqNJ5ianF.nRht2l/HRhr6zmCp9vYUvvsqynflf9e"},
+	{"\xa3ab", "$2a$05$/OK.fbVrR/bpIqNJ5ianF.6IflQkJytoRVc1yuaNtHfiuq.FRlSIS"},
+	{"1\xa3345", "$2y$05$/OK.fbVrR/bpIqNJ5ianF.ykhStlibHmUn6GomsWXnJvyvZx4vmvy"},
+	// The "$2a$" countermeasure changes the hash of this password only.
+	{"\xff\xff\xa3", "$2a$05$/OK.fbVrR/bpIqNJ5ianF.nqd1wy.pTMdcvrRWxyiGL2eMz.2a85."},
+	{"\xff\xff\xa3", "$2b$05$/OK.fbVrR/bpIqNJ5ianF.CE5elHaaO4EbggVDjb8P19RukzXSM3e"},
+	{"\xff\xff\xa3", "$2y$05$/OK.fbVrR/bpIqNJ5ianF.CE5elHaaO4EbggVDjb8P19RukzXSM3e"},
+	{"password", "$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW"},
+}
+
+func TestBcryptHash(t *testing.T) {
+	for _, tc := range bcryptTests {
+		got, err := BcryptHash(tc.password, tc.hash)
+		if err != nil {
+			t.Fatalf("BcryptHash(%q) error = %v", tc.hash, err)
+		}
+		if got != tc.hash {
+			t.Errorf("BcryptHash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+		if err := BcryptPasswordVerify(tc.password, tc.hash); err != nil {
+			t.Errorf("BcryptPasswordVerify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := BcryptPasswordVerify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("BcryptPasswordVerify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := BcryptPasswordVerify(tc.password+"x", tc.hash); len(tc.password) < 72 && !errors.Is(err, ErrMismatch) {
+			t.Errorf("BcryptPasswordVerify(%q, %q) error = %v, want ErrMismatch", tc.password+"x", tc.hash, err)
+		}
+	}
+
+	// Only the first 72 bytes of the password count.
+	long := strings.Repeat("0123456789", 8)
+	h1, _ := BcryptHash(long[:72], "$2b$04$abcdefghijklmnopqrstuu")
+	h2, _ := BcryptHash(long, "$2b$04$abcdefghijklmnopqrstuu")
+	h3, _ := BcryptHash(long[:71], "$2b$04$abcdefghijklmnopqrstuu")
+	if h1 != h2 || h1 == h3 {
+		t.Errorf("BcryptHash() of 71, 72 and 80 bytes = %v, %v, %v", h3, h1, h2)
+	}
+
+	// Unused bits of the last salt character are cleared.
+	want := "$2b$04$abcdefghijklmnopqrstuughE8Ev8uGFaUgY2cNEySvxngrb/Jzdm"
+	for _, setting := range []string{"$2b$04$abcdefghijklmnopqrstuu", "$2b$04$abcdefghijklmnopqrstuv$", "$2b$04$abcdefghijklmnopqrstuyxyz"} {
+		if got, err := BcryptHash("password", setting); got != want {
+			t.Errorf("BcryptHash(%q) = %v, %v, want %v", setting, got, err, want)
+		}
+	}
+}
+
+func TestBcryptErrors(t *testing.T) {
+	settings := []struct {
+		setting string
+		want    error
+	}{
+		{"$2b$04$abcdefghijklmnopqrstu", ErrSaltTooShort},
+		{"$2b", ErrSaltTooShort},
+		{"$2x$05$abcdefghijklmnopqrstuu", ErrInvalidSalt},
+		{"$1$05$abcdefghijklmnopqrstuu", ErrInvalidSalt},
+		{"$2b$05$abcdefghijk!mnopqrstuu", ErrInvalidSalt},
+		{"$2b$03$abcdefghijklmnopqrstuu", ErrInvalidCost},
+		{"$2b$32$abcdefghijklmnopqrstuu", ErrInvalidCost},
+		{"$2b$5$abcdefghijklmnopqrstuu", ErrInvalidCost},
+	}
+	for _, tc := range settings {
+		if _, err := BcryptHash("password", tc.setting); !errors.Is(err, tc.want) {
+			t.Errorf("BcryptHash(%q) error = %v, want %v", tc.setting, err, tc.want)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"$2b$04$abcdefghijklmnopqrstuu", 29, 0},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avT", 59, 0},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTWx", 60, 'x'},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7_88gLW7DiF2.D.avTW", 42, '_'},
+		{"$2b$04$abcdefghijklmnopqrstu/utqifOaYVU3C7488gLW7DiF2.D.avTW", 28, '/'},
+		{"{CRYPT}$2b$99$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", 11, '9'},
+	}
+	for _, tc := range testCases {
+		err := BcryptPasswordVerify("password", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("BcryptPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("BcryptPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
+
+func BenchmarkBcryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		BcryptHash("password", "$2b$05$abcdefghijklmnopqrstuu")
+	}
+}
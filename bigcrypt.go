@@ -0,0 +1,68 @@
+package descrypt
+
+import "crypto/subtle"
+
+// bigcrypt, used by HP-UX trusted systems, extends traditional crypt(3) to
+// long passwords. The first 8 characters give an ordinary 13-character DES
+// crypt hash; each further group of up to 8 characters is hashed the same
+// way under a salt made of the first two hash characters of the previous
+// segment, and its 11 hash characters are appended.
+
+// bigCryptMaxPassword is the longest password bigcrypt hashes; further
+// characters are ignored, as on HP-UX and in libxcrypt.
+const bigCryptMaxPassword = 128
+
+// BigCryptHash computes the bigcrypt hash for a password and salt (2 chars) in pure Go
+// Returns 13 characters for passwords of up to 8 characters, plus 11 for each further 8
+// Disabling "des" disables it too, since its hash of a short password is
+// a DES crypt hash.
+func BigCryptHash(password, salt string) (string, error) {
+	if methodDisabled("des") || methodDisabled("bigcrypt") {
+		return "", ErrMethodDisabled
+	}
+	s, err := parseSalt(salt)
+	if err != nil {
+		return "", err
+	}
+	return string(bigCrypt(nil, password, [2]byte{salt[0], salt[1]}, s)), nil
+}
+
+// BigCryptPasswordVerify verifies a password against a bigcrypt hash (13, 24, 35, ... chars)
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a bigcrypt
+// hash. A 13-character hash is an ordinary DES crypt hash. A "{CRYPT}"
+// prefix is accepted. The final comparison is constant-time.
+func BigCryptPasswordVerify(inputPassword string, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	n := 13
+	if len(hash) > n {
+		n += (len(hash) - n + 10) / 11 * 11
+	}
+	if pe := checkEncoded(hash, 0, n); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	s, _ := saltValue([2]byte{hash[0], hash[1]})
+	got := bigCrypt(make([]byte, 0, len(hash)), inputPassword, [2]byte{hash[0], hash[1]}, s)
+	if subtle.ConstantTimeCompare(got, []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// bigCrypt appends the bigcrypt hash of password under the salt with
+// characters salt and value s to dst.
+func bigCrypt(dst []byte, password string, salt [2]byte, s uint32) []byte {
+	password = password[:min(len(password), bigCryptMaxPassword)]
+	var out [13]byte
+	desCrypt(&out, desKey(password), salt, saltMask(s, 12))
+	dst = append(dst, out[:]...)
+	for i := 8; i < len(password); i += 8 {
+		salt = [2]byte{out[2], out[3]}
+		s, _ = saltValue(salt)
+		desCrypt(&out, desKey(password[i:]), salt, saltMask(s, 12))
+		dst = append(dst, out[2:]...)
+	}
+	return dst
+}
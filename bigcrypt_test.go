@@ -0,0 +1,91 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// bigcrypt vectors from passlib and Authen::Passphrase.
+var bigCryptTests = []struct {
+	password string
+	hash     string
+}{
+	{"passphrase", "qiyh4XPJGsOZ2MEAyLkfWqeQ"},
+	{"This is very long passwd", "f8.SVpL2fvwjkAnxn8/rgTkwvrif6bjYB5c"},
+}
+
+func TestBigCryptHash(t *testing.T) {
+	for _, tc := range bigCryptTests {
+		got, err := BigCryptHash(tc.password, tc.hash[:2])
+		if err != nil {
+			t.Fatalf("BigCryptHash() error = %v", err)
+		}
+		if got != tc.hash {
+			t.Errorf("BigCryptHash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+	}
+
+	// Up to 8 characters, bigcrypt is DES crypt.
+	for _, pw := range []string{"", "abc", "12345678"} {
+		want, _ := DESCryptHash(pw, "ab")
+		if got, _ := BigCryptHash(pw, "ab"); got != want {
+			t.Errorf("BigCryptHash(%q) = %v, want %v", pw, got, want)
+		}
+	}
+
+	// Segments count per started group of 8, up to 128 characters.
+	for _, n := range []int{9, 16, 17, 128, 129, 500} {
+		got, _ := BigCryptHash(strings.Repeat("x", n), "ab")
+		want := 13 + 11*((min(n, 128)+7)/8-1)
+		if len(got) != want {
+			t.Errorf("BigCryptHash() of %d characters has length %d, want %d", n, len(got), want)
+		}
+	}
+}
+
+func TestBigCryptPasswordVerify(t *testing.T) {
+	for _, tc := range bigCryptTests {
+		if err := BigCryptPasswordVerify(tc.password, tc.hash); err != nil {
+			t.Errorf("BigCryptPasswordVerify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := BigCryptPasswordVerify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("BigCryptPasswordVerify() error = %v with {CRYPT} prefix", err)
+		}
+		// Same first 8 characters, different tail.
+		wrong := tc.password[:8] + "x"
+		if err := BigCryptPasswordVerify(wrong, tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("BigCryptPasswordVerify(%q, %q) error = %v, want ErrMismatch", wrong, tc.hash, err)
+		}
+	}
+	if err := BigCryptPasswordVerify("SecretPassword123", "rq/N3gSWdwWeA"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("BigCryptPasswordVerify() of a truncated DES match error = %v, want ErrMismatch", err)
+	}
+	if err := BigCryptPasswordVerify("Secret", "rqWkpvtApyoVo"); err != nil {
+		t.Errorf("BigCryptPasswordVerify() of a DES hash error = %v", err)
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"qiyh4XPJGsOZ", 12, 0},
+		{"qiyh4XPJGsOZ2M", 14, 0},
+		{"qiyh4XPJGsOZ2MEAyLkfWqe", 23, 0},
+		{"qiyh4XPJGsOZ2MEA*LkfWqeQ", 16, '*'},
+		{"{CRYPT}qiyh4XPJGsOZ2MEAyLkfWqe", 30, 0},
+	}
+	for _, tc := range testCases {
+		err := BigCryptPasswordVerify("passphrase", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("BigCryptPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("BigCryptPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
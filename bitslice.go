@@ -0,0 +1,148 @@
+package descrypt
+
+//go:generate go run gen_sbox.go
+
+// The bitsliced engine runs up to 256 DES-crypt computations at once. Row i
+// of a bitsliced block holds bit i of the block for every lane, with lane n
+// in bit n%64 of word n/64, so each boolean operation in the S-box circuits
+// acts on all lanes of a word together. The pure-Go backend in
+// sbox_bitslice.go works one 64-bit word at a time; on amd64 the SSE2 and
+// AVX2 backends in sbox_amd64.s take two or four words per instruction.
+
+const (
+	// bsWords is the number of 64-bit words per row, enough for the
+	// widest (AVX2) backend.
+	bsWords = 4
+
+	// bsLanes is the number of computations a bitsliced batch holds.
+	bsLanes = 64 * bsWords
+)
+
+// bsWord is one row of a bitsliced value.
+type bsWord [bsWords]uint64
+
+// bsHalf is a bitsliced 32-bit half block.
+type bsHalf [32]bsWord
+
+// bsKey is a bitsliced 64-bit DES key.
+type bsKey [64]bsWord
+
+// bsRound XORs the DES round function of r into l for the first words
+// words of every row, given the E-box selection e and the key bits kb of
+// the round. It is set to the fastest backend the CPU supports at init.
+var bsRound = bsRoundGeneric
+
+// bsBackend names an implementation of bsRound.
+type bsBackend struct {
+	name  string
+	round func(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)
+}
+
+// bsBackends lists the bsRound implementations usable on this machine.
+var bsBackends = []bsBackend{{"generic", bsRoundGeneric}}
+
+// Index tables for the bitsliced rounds, derived at package init.
+var (
+	// bsKeyBits[i][j] is the bit of the 64-bit DES key that becomes bit j
+	// of the 48-bit round key for round i.
+	bsKeyBits [16][48]uint8
+
+	// bsPBits[s][k] is the bit of the round function output that S-box s
+	// output k lands on after the P permutation.
+	bsPBits [8][4]uint8
+
+	bsSboxes = [8]func(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64){
+		s1, s2, s3, s4, s5, s6, s7, s8,
+	}
+)
+
+func init() {
+	var cd [56]uint8
+	for i, src := range pc1Table {
+		cd[i] = src - 1
+	}
+	for i, s := range keyShifts {
+		for ; s > 0; s-- {
+			c0, d0 := cd[0], cd[28]
+			copy(cd[0:27], cd[1:28])
+			copy(cd[28:55], cd[29:56])
+			cd[27], cd[55] = c0, d0
+		}
+		for j, src := range pc2Table {
+			bsKeyBits[i][j] = cd[src-1]
+		}
+	}
+
+	for j, src := range pTable {
+		bsPBits[(src-1)/4][(src-1)%4] = uint8(j)
+	}
+}
+
+// bsExpansion returns the E-box bit selection for a 12-bit crypt(3) salt:
+// entry j is the right-half bit that feeds S-box input j.
+func bsExpansion(salt uint32) *[48]uint8 {
+	e := new([48]uint8)
+	for j, src := range eTable {
+		e[j] = src - 1
+	}
+	for i := 0; i < 12; i++ {
+		if salt>>i&1 != 0 {
+			e[i], e[i+24] = e[i+24], e[i]
+		}
+	}
+	return e
+}
+
+func bsRoundGeneric(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int) {
+	for w := 0; w < words; w++ {
+		for s, sbox := range bsSboxes {
+			x, y := e[6*s:6*s+6], kb[6*s:6*s+6]
+			o1, o2, o3, o4 := sbox(r[x[0]][w]^k[y[0]][w], r[x[1]][w]^k[y[1]][w], r[x[2]][w]^k[y[2]][w],
+				r[x[3]][w]^k[y[3]][w], r[x[4]][w]^k[y[4]][w], r[x[5]][w]^k[y[5]][w])
+			p := &bsPBits[s]
+			l[p[0]][w] ^= o1
+			l[p[1]][w] ^= o2
+			l[p[2]][w] ^= o3
+			l[p[3]][w] ^= o4
+		}
+	}
+}
+
+// bsBatch is a set of up to bsLanes keys in bitsliced form.
+type bsBatch struct {
+	key   bsKey
+	words int // number of words holding loaded lanes
+}
+
+// setKey loads a 64-bit DES key into the given lane.
+func (b *bsBatch) setKey(lane int, key uint64) {
+	w, n := lane/64, lane%64
+	for i := range b.key {
+		b.key[i][w] |= (key >> (63 - i) & 1) << n
+	}
+	b.words = max(b.words, w+1)
+}
+
+// encrypt runs count DES encryptions of the block (l, r) in every lane,
+// with the E-box selection e. As with keySchedule.encrypt, the block is in
+// initial-permutation order and FP/IP between encryptions are skipped.
+func (b *bsBatch) encrypt(l, r *bsHalf, e *[48]uint8, count int) {
+	for ; count > 0; count-- {
+		for i := 0; i < 16; i++ {
+			bsRound(l, r, &b.key, e, &bsKeyBits[i], b.words)
+			l, r = r, l
+		}
+		*l, *r = *r, *l
+	}
+}
+
+// bsLane extracts the block of one lane from bitsliced halves.
+func bsLane(l, r *bsHalf, lane int) (uint32, uint32) {
+	w, n := lane/64, lane%64
+	var lo, ro uint32
+	for i := 0; i < 32; i++ {
+		lo |= uint32(l[i][w]>>n&1) << (31 - i)
+		ro |= uint32(r[i][w]>>n&1) << (31 - i)
+	}
+	return lo, ro
+}
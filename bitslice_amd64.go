@@ -0,0 +1,41 @@
+//go:build amd64 && !purego
+
+package descrypt
+
+func init() {
+	bsBackends = append(bsBackends, bsBackend{"SSE2", bsRoundSSE2})
+	bsRound = bsRoundSSE2
+	if hasAVX2() {
+		bsBackends = append(bsBackends, bsBackend{"AVX2", bsRoundAVX2})
+		bsRound = bsRoundAVX2
+	}
+}
+
+//go:noescape
+func bsRoundSSE2(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)
+
+//go:noescape
+func bsRoundAVX2(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+// hasAVX2 reports whether the CPU supports AVX2 and the OS saves the YMM
+// registers across context switches.
+func hasAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
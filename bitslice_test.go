@@ -0,0 +1,59 @@
+package descrypt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBitsliceSboxes(t *testing.T) {
+	// Lane v of each input word carries bit (5-i) of v, so lane v
+	// evaluates the S-box at index v.
+	var a [6]uint64
+	for v := 0; v < 64; v++ {
+		for i := range a {
+			a[i] |= uint64(v>>(5-i)&1) << v
+		}
+	}
+	for s, sbox := range bsSboxes {
+		o1, o2, o3, o4 := sbox(a[0], a[1], a[2], a[3], a[4], a[5])
+		for v := 0; v < 64; v++ {
+			got := o1>>v&1<<3 | o2>>v&1<<2 | o3>>v&1<<1 | o4>>v&1
+			row := (v>>4)&2 | v&1
+			col := (v >> 1) & 15
+			if want := uint64(sBoxes[s][row*16+col]); got != want {
+				t.Errorf("s%d(%#x) = %d, want %d", s+1, v, got, want)
+			}
+		}
+	}
+}
+
+func TestBitsliceBackends(t *testing.T) {
+	passwords := make([]string, bsLanes+70)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("lane%d\xa5%d", i, i*i)
+	}
+	defer func(round func(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)) {
+		bsRound = round
+	}(bsRound)
+
+	for _, be := range bsBackends {
+		t.Run(be.name, func(t *testing.T) {
+			bsRound = be.round
+			for _, salt := range []string{"rq", "./", "zz", "Q7"} {
+				// Cover partial batches of each width as well as full ones.
+				for _, n := range []int{1, 63, 64, 65, 129, 200, len(passwords)} {
+					hashes, err := DESCryptHashBatch(passwords[:n], salt)
+					if err != nil {
+						t.Fatalf("DESCryptHashBatch() error = %v", err)
+					}
+					for i, pw := range passwords[:n] {
+						want, _ := DESCryptHash(pw, salt)
+						if hashes[i] != want {
+							t.Fatalf("DESCryptHashBatch()[%d] = %v, want %v for n=%d salt '%s'", i, hashes[i], want, n, salt)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrMethodDisabled means the method policy forbids making new hashes in
+// the scheme a setting selects.
+var ErrMethodDisabled = errors.New("hashing method disabled by policy")
+
+// SaltStatus classifies a setting as libxcrypt's crypt_checksalt does.
+type SaltStatus int
+
+const (
+	// SaltOK means the setting is valid and its scheme is current.
+	SaltOK SaltStatus = iota
+	// SaltInvalid means the setting cannot be hashed under.
+	SaltInvalid
+	// SaltMethodDisabled means the method policy forbids new hashes in
+	// the setting's scheme.
+	SaltMethodDisabled
+	// SaltMethodLegacy means the setting's scheme is too weak for new
+	// hashes: a DES variant, MD5-crypt or APR1.
+	SaltMethodLegacy
+	// SaltTooCheap means the setting asks for less work than the
+	// scheme's default cost.
+	SaltTooCheap
+)
+
+func (s SaltStatus) String() string {
+	switch s {
+	case SaltOK:
+		return "ok"
+	case SaltInvalid:
+		return "invalid"
+	case SaltMethodDisabled:
+		return "method disabled"
+	case SaltMethodLegacy:
+		return "method legacy"
+	case SaltTooCheap:
+		return "too cheap"
+	}
+	return "unknown"
+}
+
+// MethodPolicy controls which schemes may make new hashes in this
+// process. It never affects verification, so logins against hashes in a
+// disabled scheme keep working.
+type MethodPolicy struct {
+	// Disabled lists schemes, named as Identify reports them, whose hash
+	// functions return ErrMethodDisabled. "des" covers DESCryptHash,
+	// HashPassword, Hasher.HashWithSalt, DESCryptHashBatch,
+	// SaltContextFor and BigCryptHash. AppendDESCrypt and the SaltContext
+	// methods, which cannot return an error, instead return no hash: dst
+	// unchanged, "" or nil. "crypt16" must be listed separately.
+	Disabled []string
+}
+
+// disabledSchemes holds the schemes of the current method policy.
+var disabledSchemes atomic.Pointer[map[string]bool]
+
+// SetMethodPolicy replaces the method policy for the whole process. The
+// zero MethodPolicy, the default, enables every scheme.
+func SetMethodPolicy(p MethodPolicy) {
+	disabled := make(map[string]bool, len(p.Disabled))
+	for _, name := range p.Disabled {
+		disabled[name] = true
+	}
+	disabledSchemes.Store(&disabled)
+}
+
+// methodDisabled reports whether the method policy disables scheme.
+func methodDisabled(scheme string) bool {
+	m := disabledSchemes.Load()
+	return m != nil && (*m)[scheme]
+}
+
+// checkMethod returns ErrMethodDisabled if the method policy disables
+// scheme.
+func checkMethod(scheme string) error {
+	if methodDisabled(scheme) {
+		return ErrMethodDisabled
+	}
+	return nil
+}
+
+// CheckSalt reports whether setting, or the setting part of a full hash,
+// is fit for making new hashes, mirroring libxcrypt's crypt_checksalt. It
+// returns the first of SaltInvalid, SaltMethodDisabled, SaltMethodLegacy
+// and SaltTooCheap that applies, or SaltOK. Settings that need fewer
+// rounds or less memory than GenerateSalt uses by default are too cheap.
+func CheckSalt(setting string) SaltStatus {
+	scheme, err := settingScheme(setting)
+	switch {
+	case err != nil:
+		return SaltInvalid
+	case methodDisabled(scheme), scheme == "bigcrypt" && methodDisabled("des"):
+		return SaltMethodDisabled
+	}
+	switch scheme {
+	case "des", "bigcrypt", "extended-des", "md5crypt", "apr1":
+		return SaltMethodLegacy
+	}
+	if def, err := GenerateSalt(scheme, 0, zeroReader{}); err == nil && settingWork(scheme, setting) < settingWork(scheme, def) {
+		return SaltTooCheap
+	}
+	return SaltOK
+}
+
+// settingScheme parses setting and returns the name of its scheme.
+func settingScheme(setting string) (string, error) {
+	if s, ok := lookupRegistered(setting); ok {
+		return s.Name, nil
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(setting, "_"):
+		_, _, err = parseExtendedSetting(setting)
+		return "extended-des", err
+	case strings.HasPrefix(setting, md5CryptPrefix):
+		_, err = parseMD5Setting(setting, md5CryptPrefix)
+		return "md5crypt", err
+	case strings.HasPrefix(setting, apr1Prefix):
+		_, err = parseMD5Setting(setting, apr1Prefix)
+		return "apr1", err
+	case strings.HasPrefix(setting, sha256CryptPrefix):
+		_, err = sha256Crypt.parseSetting(setting)
+		return "sha256crypt", err
+	case strings.HasPrefix(setting, sha512CryptPrefix):
+		_, err = sha512Crypt.parseSetting(setting)
+		return "sha512crypt", err
+	case strings.HasPrefix(setting, "$2"):
+		_, _, _, err = parseBcryptSetting(setting)
+		return "bcrypt", err
+	case strings.HasPrefix(setting, yescryptPrefix):
+		_, _, _, err = parseYescryptSetting(setting)
+		return "yescrypt", err
+	}
+	if strings.HasPrefix(setting, "$") {
+		return "", ErrUnknownScheme
+	}
+	if _, err = parseSalt(setting); len(setting) > 13 {
+		return "bigcrypt", err
+	}
+	return "des", err
+}
@@ -0,0 +1,120 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckSalt(t *testing.T) {
+	testCases := []struct {
+		setting string
+		want    SaltStatus
+	}{
+		{"rq", SaltMethodLegacy},
+		{"rq/N3gSWdwWeA", SaltMethodLegacy},
+		{"r", SaltInvalid},
+		{"r!", SaltInvalid},
+		{"_J9..CCCC", SaltMethodLegacy},
+		{"_....CCCC", SaltInvalid},
+		{"$1$abc", SaltMethodLegacy},
+		{"$apr1$abc", SaltMethodLegacy},
+		{"$5$saltstring", SaltOK},
+		{"$5$rounds=5000$saltstring", SaltOK},
+		{"$6$rounds=1000$saltstring", SaltTooCheap},
+		{"$6$salt:string", SaltInvalid},
+		{"$2b$05$abcdefghijklmnopqrstu.", SaltOK},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", SaltTooCheap},
+		{"$2x$05$abcdefghijklmnopqrstu.", SaltInvalid},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1", SaltOK},
+		{"$y$jA.$abcdefgh", SaltTooCheap},
+		{"$y$jET$abcdefgh", SaltOK},
+		{"$9$abc", SaltInvalid},
+		{"", SaltInvalid},
+	}
+	for _, tc := range testCases {
+		if got := CheckSalt(tc.setting); got != tc.want {
+			t.Errorf("CheckSalt(%q) = %v, want %v", tc.setting, got, tc.want)
+		}
+	}
+}
+
+func TestMethodPolicy(t *testing.T) {
+	// A context made before the policy must not keep hashing after it.
+	ctx, err := SaltContextFor("rq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only a disabled method makes HashBatch return nil.
+	if got := ctx.HashBatch(nil); got == nil {
+		t.Error("SaltContext.HashBatch(nil) = nil with DES enabled")
+	}
+	if got, err := DESCryptHashBatch(nil, "rq"); got == nil || err != nil {
+		t.Errorf("DESCryptHashBatch(nil) = %v, %v with DES enabled", got, err)
+	}
+	SetMethodPolicy(MethodPolicy{Disabled: []string{"des", "sha256crypt"}})
+	defer SetMethodPolicy(MethodPolicy{})
+
+	if got := CheckSalt("rq"); got != SaltMethodDisabled {
+		t.Errorf("CheckSalt(%q) = %v, want %v", "rq", got, SaltMethodDisabled)
+	}
+	if got := CheckSalt("$5$saltstring"); got != SaltMethodDisabled {
+		t.Errorf("CheckSalt(%q) = %v, want %v", "$5$saltstring", got, SaltMethodDisabled)
+	}
+	if got := CheckSalt("rqXexS6ZhobKA" + "abcdefghijk"); got != SaltMethodDisabled {
+		t.Errorf("CheckSalt(bigcrypt) = %v, want %v", got, SaltMethodDisabled)
+	}
+	if got := CheckSalt("$6$saltstring"); got != SaltOK {
+		t.Errorf("CheckSalt(%q) = %v, want %v", "$6$saltstring", got, SaltOK)
+	}
+
+	hashers := map[string]func() (string, error){
+		"DESCryptHash":     func() (string, error) { return DESCryptHash("SecretPassword123", "rq") },
+		"HashPassword":     func() (string, error) { return HashPassword("SecretPassword123") },
+		"Crypt":            func() (string, error) { return Crypt("SecretPassword123", "rq") },
+		"SHA256CryptHash":  func() (string, error) { return SHA256CryptHash("Hello world!", "$5$saltstring") },
+		"Crypt($5$)":       func() (string, error) { return Crypt("Hello world!", "$5$saltstring") },
+		"BigCryptHash":     func() (string, error) { return BigCryptHash("secret", "ab") },
+		"Crypt(bigcrypt)":  func() (string, error) { return Crypt("secret", "abcdefghijklmn") },
+		"Policy(bigcrypt)": func() (string, error) { return Policy{Scheme: "bigcrypt"}.Hash("secret") },
+		"SaltContextFor": func() (string, error) {
+			_, err := SaltContextFor("rq")
+			return "", err
+		},
+		"DESCryptHashBatch": func() (string, error) {
+			_, err := DESCryptHashBatch([]string{"a"}, "rq")
+			return "", err
+		},
+		"DESCryptHashBatch(nil)": func() (string, error) {
+			_, err := DESCryptHashBatch(nil, "rq")
+			return "", err
+		},
+	}
+	for name, hash := range hashers {
+		if _, err := hash(); !errors.Is(err, ErrMethodDisabled) {
+			t.Errorf("%s() error = %v, want ErrMethodDisabled", name, err)
+		}
+	}
+	if got := AppendDESCrypt(nil, []byte("SecretPassword123"), [2]byte{'r', 'q'}); len(got) != 0 {
+		t.Errorf("AppendDESCrypt() = %q with DES disabled", got)
+	}
+	if got := ctx.Hash("SecretPassword123"); got != "" {
+		t.Errorf("SaltContext.Hash() = %q with DES disabled", got)
+	}
+	if got := ctx.HashBatch([]string{"SecretPassword123"}); got != nil {
+		t.Errorf("SaltContext.HashBatch() = %q with DES disabled", got)
+	}
+	if _, err := SHA512CryptHash("Hello world!", "$6$saltstring"); err != nil {
+		t.Errorf("SHA512CryptHash() error = %v with only SHA-256 disabled", err)
+	}
+
+	// Verification keeps working.
+	if err := DESPasswordVerify("SecretPassword123", "rq/N3gSWdwWeA"); err != nil {
+		t.Errorf("DESPasswordVerify() error = %v with DES disabled", err)
+	}
+	if err := Verify("Hello world!", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"); err != nil {
+		t.Errorf("Verify() error = %v with SHA-256 disabled", err)
+	}
+	if !VerifyDESCrypt([]byte("SecretPassword123"), []byte("rq/N3gSWdwWeA")) {
+		t.Error("VerifyDESCrypt() = false with DES disabled")
+	}
+}
@@ -0,0 +1,178 @@
+package descrypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPassword means a password has a byte that traditional DES
+// crypt cannot represent: a NUL, which C callers cannot pass, or an 8-bit
+// character, whose top bit is dropped.
+var ErrInvalidPassword = errors.New("password has a NUL or 8-bit character")
+
+// Compat selects how traditional DES crypt treats the inputs crypt(3)
+// implementations disagree on. The zero Compat keeps this package's own
+// behavior: salt characters must be in itoa64, and every password byte
+// up to the eighth is hashed, NUL or not.
+//
+// Every crypt(3) drops the top bit of each password byte, so passwords
+// differing only there hash alike in all modes.
+type Compat int
+
+const (
+	// Strict rejects what the C implementations would handle lossily:
+	// salt characters outside itoa64 give ErrInvalidSalt, and passwords
+	// with a NUL or 8-bit byte give ErrInvalidPassword.
+	Strict Compat = iota + 1
+	// GlibcLegacy matches UFC-crypt as shipped in glibc before it began
+	// rejecting invalid salts. The password ends at its first NUL. Any
+	// salt character is accepted and contributes the low 6 bits of its
+	// distance, as a signed char, from '.', 'A' - 12 or 'a' - 38. A
+	// one-character salt hashes with a NUL as its second character but
+	// repeats the first in the output.
+	GlibcLegacy
+	// Musl matches musl's crypt_des.c. The password ends at its first
+	// NUL, and salt characters map as for GlibcLegacy, except that NUL,
+	// newline and ':' are rejected, so a salt needs two characters.
+	Musl
+	// FreeBSD matches FreeBSD's crypt-des.c. The password ends at its
+	// first NUL, and salt characters outside itoa64 count as '.'. A
+	// one-character salt hashes as if its second character were '.', where
+	// GlibcLegacy's NUL counts as 18, so only the repeated first
+	// character in the output is shared.
+	FreeBSD
+)
+
+// WithCompat makes Crypt and Verify, for traditional DES, treat salts and
+// passwords as c does. Verify then accepts hashes made by a C library with
+// salt characters outside itoa64, and is constant-time only in its final
+// comparison.
+func WithCompat(c Compat) CryptOption {
+	return func(o *cryptOptions) {
+		o.compat = c
+	}
+}
+
+// password returns what a C crypt(3) sees of password under c.
+func (c Compat) password(password string) (string, error) {
+	i := strings.IndexByte(password, 0)
+	if c == Strict {
+		for j := 0; j < len(password); j++ {
+			if password[j] == 0 || password[j] >= 0x80 {
+				return "", ErrInvalidPassword
+			}
+		}
+	}
+	if i >= 0 {
+		password = password[:i]
+	}
+	return password, nil
+}
+
+// salt returns the 12-bit salt value of salt under c and the two
+// characters the hash begins with.
+func (c Compat) salt(salt string) (uint32, [2]byte, error) {
+	if c == Strict {
+		s, err := parseSalt(salt)
+		if err != nil {
+			return 0, [2]byte{}, err
+		}
+		return s, [2]byte{salt[0], salt[1]}, nil
+	}
+	// A C string ends at its first NUL.
+	if i := strings.IndexByte(salt, 0); i >= 0 {
+		salt = salt[:i]
+	}
+	if len(salt) == 0 || c == Musl && len(salt) < 2 {
+		return 0, [2]byte{}, &ParseError{Pos: len(salt), EOF: true, Err: ErrSaltTooShort}
+	}
+	var chars [2]byte
+	copy(chars[:], salt)
+	var s uint32
+	for i, ch := range chars {
+		if c == Musl && (ch == '\n' || ch == ':') {
+			return 0, [2]byte{}, &ParseError{Pos: i, Char: ch, Err: ErrInvalidSalt}
+		}
+		var v uint32
+		if c == FreeBSD {
+			v = freeBSDSaltValue(ch)
+		} else {
+			v = ufcSaltValue(ch)
+		}
+		s |= v << (6 * i)
+	}
+	if chars[1] == 0 {
+		chars[1] = chars[0]
+	}
+	return s, chars, nil
+}
+
+// ufcSaltValue is the 6-bit value of a salt character in UFC-crypt and
+// musl: its distance as a signed char from the start of its itoa64 range,
+// or from '.' if below 'A', modulo 64.
+func ufcSaltValue(ch byte) uint32 {
+	sch := int(int8(ch))
+	switch {
+	case sch >= 'a':
+		sch -= 'a' - 38
+	case sch >= 'A':
+		sch -= 'A' - 12
+	default:
+		sch -= '.'
+	}
+	return uint32(sch) & 63
+}
+
+// freeBSDSaltValue is the 6-bit value of a salt character in the BSDs:
+// its index in itoa64, or 0 if it is not there.
+func freeBSDSaltValue(ch byte) uint32 {
+	return uint32(max(strings.IndexByte(itoa64, ch), 0))
+}
+
+// desCryptCompat computes the DES crypt(3) hash of password under salt as
+// the platform c does.
+func desCryptCompat(password, salt string, c Compat) (string, error) {
+	if err := checkMethod("des"); err != nil {
+		return "", err
+	}
+	password, err := c.password(password)
+	if err != nil {
+		return "", err
+	}
+	s, chars, err := c.salt(salt)
+	if err != nil {
+		return "", err
+	}
+	var out [13]byte
+	desCrypt(&out, desKey(password), chars, saltMask(s, 12))
+	return string(out[:]), nil
+}
+
+// verifyDESCompat checks password against a traditional DES crypt hash
+// made on the platform c, whose salt characters may lie outside itoa64.
+func verifyDESCompat(password, storedHash string, c Compat) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	if pe := checkEncoded(hash, 2, 13); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+	if i := strings.IndexByte(hash[:2], 0); i >= 0 {
+		return &ParseError{Pos: offset + i, Err: ErrMalformedHash}
+	}
+	s, chars, err := c.salt(hash[:2])
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	password, err = c.password(password)
+	if err != nil {
+		return err
+	}
+	var got [13]byte
+	desCrypt(&got, desKey(password), chars, saltMask(s, 12))
+	if subtle.ConstantTimeCompare(got[:], []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
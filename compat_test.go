@@ -0,0 +1,113 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompat(t *testing.T) {
+	des := func(password, salt string) string {
+		h, err := DESCryptHash(password, salt)
+		if err != nil {
+			t.Fatalf("DESCryptHash(%q, %q) error = %v", password, salt, err)
+		}
+		return h
+	}
+	// Each case gives the itoa64 salt whose hash the compat hash must
+	// equal apart from the first two characters.
+	testCases := []struct {
+		compat   Compat
+		password string
+		salt     string
+		want     string
+	}{
+		{GlibcLegacy, "password", "rq", "rq" + des("password", "rq")[2:]},
+		{GlibcLegacy, "password", "!!", "!!" + des("password", "nn")[2:]},
+		{GlibcLegacy, "password", "{~", "{~" + des("password", ".1")[2:]},
+		{GlibcLegacy, "password", "\x80\xff", "\x80\xff" + des("password", "GF")[2:]},
+		{GlibcLegacy, "password", "a", "aa" + des("password", "aG")[2:]},
+		{GlibcLegacy, "pass\x00word", "ab", des("pass", "ab")},
+		{GlibcLegacy, "\xf0\xe1\xf3\xf3", "ab", des("pass", "ab")},
+		{Musl, "password", "!!", "!!" + des("password", "nn")[2:]},
+		{Musl, "password", "\x80\xff", "\x80\xff" + des("password", "GF")[2:]},
+		{Musl, "pass\x00word", "ab", des("pass", "ab")},
+		{FreeBSD, "password", "!!", "!!" + des("password", "..")[2:]},
+		{FreeBSD, "password", "\x80z", "\x80z" + des("password", ".z")[2:]},
+		{FreeBSD, "password", "a", "aa" + des("password", "a.")[2:]},
+		{FreeBSD, "pass\x00word", "ab", des("pass", "ab")},
+		{Strict, "password", "rq", des("password", "rq")},
+	}
+	for _, tc := range testCases {
+		if got, err := Crypt(tc.password, tc.salt, WithCompat(tc.compat)); err != nil || got != tc.want {
+			t.Errorf("Crypt(%q, %q, WithCompat(%d)) = %q, %v, want %q", tc.password, tc.salt, tc.compat, got, err, tc.want)
+		}
+		if tc.salt == tc.want[:2] {
+			if err := Verify(tc.password, tc.want, WithCompat(tc.compat)); err != nil {
+				t.Errorf("Verify(%q, %q, WithCompat(%d)) error = %v", tc.password, tc.want, tc.compat, err)
+			}
+			if err := Verify("wrong", "{CRYPT}"+tc.want, WithCompat(tc.compat)); !errors.Is(err, ErrMismatch) {
+				t.Errorf("Verify(%q, %q, WithCompat(%d)) error = %v, want ErrMismatch", "wrong", tc.want, tc.compat, err)
+			}
+		}
+	}
+
+	// Without a compat mode, bytes after a NUL count.
+	if des("pass\x00word", "ab") == des("pass", "ab") {
+		t.Error("DESCryptHash() ignored bytes after NUL without WithCompat")
+	}
+}
+
+func TestCompatOneCharSalt(t *testing.T) {
+	// Known answers for Crypt("pw", "a"); the modes that accept a
+	// one-character salt differ in what the missing character counts as.
+	testCases := []struct {
+		compat Compat
+		want   string
+		err    error
+	}{
+		{0, "", ErrSaltTooShort},
+		{Strict, "", ErrSaltTooShort},
+		{GlibcLegacy, "aasNYFjLLjzXY", nil},
+		{Musl, "", ErrSaltTooShort},
+		{FreeBSD, "aahXJwB2XNXxE", nil},
+	}
+	for _, tc := range testCases {
+		got, err := Crypt("pw", "a", WithCompat(tc.compat))
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("Crypt(%q, %q, WithCompat(%d)) = %q, %v, want %q, %v", "pw", "a", tc.compat, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestCompatErrors(t *testing.T) {
+	testCases := []struct {
+		compat   Compat
+		password string
+		salt     string
+		err      error
+	}{
+		{Strict, "password", "!!", ErrInvalidSalt},
+		{Strict, "pass\x00word", "ab", ErrInvalidPassword},
+		{Strict, "pässword", "ab", ErrInvalidPassword},
+		{GlibcLegacy, "password", "", ErrSaltTooShort},
+		{GlibcLegacy, "password", "\x00a", ErrSaltTooShort},
+		{Musl, "password", "a", ErrSaltTooShort},
+		{Musl, "password", "a:", ErrInvalidSalt},
+		{Musl, "password", "\na", ErrInvalidSalt},
+		{FreeBSD, "password", "", ErrSaltTooShort},
+	}
+	for _, tc := range testCases {
+		if _, err := Crypt(tc.password, tc.salt, WithCompat(tc.compat)); !errors.Is(err, tc.err) {
+			t.Errorf("Crypt(%q, %q, WithCompat(%d)) error = %v, want %v", tc.password, tc.salt, tc.compat, err, tc.err)
+		}
+	}
+
+	for _, hash := range []string{"!!", "a:abcdefghijk", "a\x00abcdefghijk", "!!abcdefghij!"} {
+		if err := Verify("password", hash, WithCompat(Musl)); !errors.Is(err, ErrMalformedHash) {
+			t.Errorf("Verify(%q, WithCompat(Musl)) error = %v, want ErrMalformedHash", hash, err)
+		}
+	}
+	if err := Verify("pässword", "rq/N3gSWdwWeA", WithCompat(Strict)); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("Verify() error = %v with Strict and an 8-bit password, want ErrInvalidPassword", err)
+	}
+}
@@ -0,0 +1,147 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Scheme is a password hashing scheme that Crypt and Verify dispatch to
+// by the prefix of the setting or stored hash.
+type Scheme struct {
+	// Name identifies the scheme, e.g. "bcrypt".
+	Name string
+	// Prefix is the start of every setting and hash of the scheme, e.g.
+	// "$2b$". It must not be empty.
+	Prefix string
+	// Hash computes the hash of password under setting.
+	Hash func(password, setting string) (string, error)
+	// Verify checks password against a stored hash. It returns nil on a
+	// match and ErrMismatch or a *ParseError otherwise.
+	Verify func(password, hash string) error
+}
+
+// ErrUnknownScheme means no registered scheme handles the prefix of a
+// setting or stored hash.
+var ErrUnknownScheme = errors.New("unknown hashing scheme")
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = []Scheme{
+		{"extended-des", "_", ExtendedDESCryptHash, ExtendedDESPasswordVerify},
+		{"md5crypt", md5CryptPrefix, MD5CryptHash, MD5CryptPasswordVerify},
+		{"apr1", apr1Prefix, APR1Hash, APR1PasswordVerify},
+		{"sha256crypt", sha256CryptPrefix, SHA256CryptHash, SHA256CryptPasswordVerify},
+		{"sha512crypt", sha512CryptPrefix, SHA512CryptHash, SHA512CryptPasswordVerify},
+		{"bcrypt", "$2a$", BcryptHash, BcryptPasswordVerify},
+		{"bcrypt", "$2b$", BcryptHash, BcryptPasswordVerify},
+		{"bcrypt", "$2y$", BcryptHash, BcryptPasswordVerify},
+		{"yescrypt", yescryptPrefix, YescryptHash, YescryptPasswordVerify},
+	}
+	// registered holds the prefixes of schemes added with RegisterScheme.
+	registered = map[string]bool{}
+)
+
+// RegisterScheme adds s to the schemes Crypt and Verify dispatch to,
+// replacing any registered scheme with the same prefix. When prefixes
+// overlap, the longest that matches wins. Identify, CheckSalt and
+// NeedsRehash then report hashes with that prefix as s.Name, with no
+// salt or cost, even for a prefix that was built in. It panics if s.Prefix is empty
+// or s lacks a Hash or Verify function.
+func RegisterScheme(s Scheme) {
+	if s.Prefix == "" || s.Hash == nil || s.Verify == nil {
+		panic("descrypt: RegisterScheme with empty prefix or nil function")
+	}
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	registered[s.Prefix] = true
+	for i := range schemes {
+		if schemes[i].Prefix == s.Prefix {
+			schemes[i] = s
+			return
+		}
+	}
+	schemes = append(schemes, s)
+}
+
+// lookupScheme returns the registered scheme with the longest prefix of
+// setting.
+func lookupScheme(setting string) (Scheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	return lookupSchemeLocked(setting)
+}
+
+// lookupRegistered returns the scheme lookupScheme picks for setting if it
+// was added with RegisterScheme rather than built in.
+func lookupRegistered(setting string) (Scheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	s, ok := lookupSchemeLocked(setting)
+	return s, ok && registered[s.Prefix]
+}
+
+func lookupSchemeLocked(setting string) (Scheme, bool) {
+	var best Scheme
+	for _, s := range schemes {
+		if strings.HasPrefix(setting, s.Prefix) && len(s.Prefix) > len(best.Prefix) {
+			best = s
+		}
+	}
+	return best, best.Prefix != ""
+}
+
+// Crypt computes the hash of password under setting, choosing the scheme
+// from the setting's prefix as crypt(3) does: "_" for extended DES, "$1$"
+// for MD5-crypt, "$5$" and "$6$" for SHA-crypt, "$2a$", "$2b$" and "$2y$"
+// for bcrypt, "$y$" for yescrypt, and any scheme added with
+// RegisterScheme. A setting with no "$" prefix is a traditional DES salt,
+// or, if longer than 13 characters, a bigcrypt hash. A full hash may be
+// passed as the setting. Returns ErrUnknownScheme for an unregistered "$"
+// prefix. With FailureTokens, any error gives a failure token instead.
+func Crypt(password, setting string, opts ...CryptOption) (string, error) {
+	o := newCryptOptions(opts)
+	hash, err := o.crypt(password, setting)
+	return o.finish(setting, hash, err)
+}
+
+func (o *cryptOptions) crypt(password, setting string) (string, error) {
+	if s, ok := lookupScheme(setting); ok {
+		if err := checkMethod(s.Name); err != nil {
+			return "", err
+		}
+		return s.Hash(password, setting)
+	}
+	switch {
+	case strings.HasPrefix(setting, "$"):
+		return "", ErrUnknownScheme
+	case len(setting) > 13:
+		return BigCryptHash(password, setting)
+	}
+	return o.desCrypt(password, setting)
+}
+
+// Verify checks password against a stored hash of any scheme Crypt
+// handles, optionally prefixed with "{CRYPT}". It returns nil if the
+// password matches, ErrMismatch if it does not, ErrUnknownScheme for an
+// unregistered "$" prefix, or a *ParseError wrapping ErrMalformedHash.
+// WithCompat and RejectOverlong apply to traditional DES hashes.
+func Verify(password, hash string, opts ...CryptOption) error {
+	h, offset := trimCryptPrefix(hash)
+	s, ok := lookupScheme(h)
+	var err error
+	switch {
+	case ok:
+		err = s.Verify(password, h)
+	case strings.HasPrefix(h, "$"):
+		return ErrUnknownScheme
+	case len(h) > 13:
+		err = BigCryptPasswordVerify(password, h)
+	default:
+		err = newCryptOptions(opts).desVerify(password, h)
+	}
+	if pe, ok := err.(*ParseError); ok && offset > 0 {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: pe.Err}
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package descrypt
+
+import "crypto/subtle"
+
+// crypt16, from Digital Ultrix and Tru64 enhanced security, hashes up to 16
+// password characters as two DES crypt halves under the same salt: the
+// first 8 characters with 20 encryptions and the next 8 with 5. The result
+// is the salt followed by the two 11-character encodings.
+
+// crypt16Len is the length of a crypt16 hash.
+const crypt16Len = 2 + 11 + 11
+
+// Crypt16Hash computes the crypt16 hash for a password and salt (2 chars) in pure Go
+// Returns a 24-character string (2-char salt + two 11-char halves)
+func Crypt16Hash(password, salt string) (string, error) {
+	if err := checkMethod("crypt16"); err != nil {
+		return "", err
+	}
+	s, err := parseSalt(salt)
+	if err != nil {
+		return "", err
+	}
+	var out [crypt16Len]byte
+	crypt16(&out, password, [2]byte{salt[0], salt[1]}, s)
+	return string(out[:]), nil
+}
+
+// Crypt16PasswordVerify verifies a password against a crypt16 hash (24 chars)
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a crypt16
+// hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func Crypt16PasswordVerify(inputPassword string, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	if pe := checkEncoded(hash, 0, crypt16Len); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	s, _ := saltValue([2]byte{hash[0], hash[1]})
+	var got [crypt16Len]byte
+	crypt16(&got, inputPassword, [2]byte{hash[0], hash[1]}, s)
+	if subtle.ConstantTimeCompare(got[:], []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// crypt16 writes the crypt16 hash of password to out, under the salt with
+// characters salt and value s.
+func crypt16(out *[crypt16Len]byte, password string, salt [2]byte, s uint32) {
+	mask := saltMask(s, 12)
+	var ks keySchedule
+	out[0] = salt[0]
+	out[1] = salt[1]
+
+	ks.init(desKey(password))
+	encodeBlock(out[2:], finalPermute(ks.encrypt(0, 0, mask, 20)))
+
+	var tail string
+	if len(password) > 8 {
+		tail = password[8:]
+	}
+	ks.init(desKey(tail))
+	encodeBlock(out[13:], finalPermute(ks.encrypt(0, 0, mask, 5)))
+}
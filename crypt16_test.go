@@ -0,0 +1,82 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+// crypt16 vectors from passlib.
+var crypt16Tests = []struct {
+	password string
+	hash     string
+}{
+	{"passphrase", "qi8H8R7OM4xMUNMPuRAZxlY."},
+	{"printf", "aaCjFz4Sh8Eg2QSqAReePlq6"},
+	{"printf", "AA/xje2RyeiSU0iBY3PDwjYo"},
+	{"LOLOAQICI82QB4IP", "/.FcK3mad6JwYt8LVmDqz9Lc"},
+	{"LOLOAQICI", "/.FcK3mad6JwYSaRHJoTPzY2"},
+}
+
+func TestCrypt16Hash(t *testing.T) {
+	for _, tc := range crypt16Tests {
+		got, err := Crypt16Hash(tc.password, tc.hash[:2])
+		if err != nil {
+			t.Fatalf("Crypt16Hash() error = %v", err)
+		}
+		if got != tc.hash {
+			t.Errorf("Crypt16Hash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+	}
+
+	// Characters past the 16th are ignored.
+	a, _ := Crypt16Hash("LOLOAQICI82QB4IP", "/.")
+	b, _ := Crypt16Hash("LOLOAQICI82QB4IPextra", "/.")
+	if a != b {
+		t.Errorf("Crypt16Hash() uses characters past the 16th: %v != %v", a, b)
+	}
+	// Up to 8 characters, the second half hashes an all-zero key.
+	c, _ := Crypt16Hash("L", "/.")
+	d, _ := Crypt16Hash("LOLOAQIC", "/.")
+	if c[13:] != d[13:] {
+		t.Errorf("Crypt16Hash() second halves differ for short passwords: %v, %v", c, d)
+	}
+	if _, err := Crypt16Hash("x", "a"); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("Crypt16Hash() error = %v, want ErrSaltTooShort", err)
+	}
+}
+
+func TestCrypt16PasswordVerify(t *testing.T) {
+	for _, tc := range crypt16Tests {
+		if err := Crypt16PasswordVerify(tc.password, tc.hash); err != nil {
+			t.Errorf("Crypt16PasswordVerify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := Crypt16PasswordVerify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("Crypt16PasswordVerify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := Crypt16PasswordVerify("x"+tc.password, tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("Crypt16PasswordVerify(%q, %q) error = %v, want ErrMismatch", "x"+tc.password, tc.hash, err)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"qi8H8R7OM4xMUNMPuRAZxlY", 23, 0},
+		{"qi8H8R7OM4xMUNMPuRAZxlY.x", 24, 'x'},
+		{"qi8H8R7OM4xM!NMPuRAZxlY.", 12, '!'},
+		{"{CRYPT}qi8H8R7OM4xMUNMPuRAZxlY", 30, 0},
+	}
+	for _, tc := range testCases {
+		err := Crypt16PasswordVerify("passphrase", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("Crypt16PasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("Crypt16PasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package descrypt
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCrypt(t *testing.T) {
+	testCases := []struct {
+		password string
+		setting  string
+		want     string
+	}{
+		{"SecretPassword123", "rq", "rq/N3gSWdwWeA"},
+		{"SecretPassword123", "rq/N3gSWdwWeA", "rq/N3gSWdwWeA"},
+		{"password", "_J9..CCCC", "_J9..CCCC.MOp/ZbelpA"},
+		{"password", "$1$abc", "$1$abc$BXBqpb9BZcZhXLgbee.0s/"},
+		{"myPassword", "$apr1$qHDFfhPC", "$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0"},
+		{"Hello world!", "$5$saltstring", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		{"Hello world!", "$6$saltstring", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{"U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+		{"password", "$2b$04$abcdefghijklmnopqrstu.", "$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW"},
+		{"password", "$y$j5.$abcdefgh", "$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9"},
+	}
+	for _, tc := range testCases {
+		got, err := Crypt(tc.password, tc.setting)
+		if err != nil || got != tc.want {
+			t.Errorf("Crypt(%q, %q) = %v, %v, want %v", tc.password, tc.setting, got, err, tc.want)
+		}
+		if err := Verify(tc.password, tc.want); err != nil {
+			t.Errorf("Verify(%q, %q) error = %v", tc.password, tc.want, err)
+		}
+		if err := Verify(tc.password, "{CRYPT}"+tc.want); err != nil {
+			t.Errorf("Verify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := Verify("x"+tc.password, tc.want); !errors.Is(err, ErrMismatch) {
+			t.Errorf("Verify(%q, %q) error = %v, want ErrMismatch", "x"+tc.password, tc.want, err)
+		}
+	}
+
+	// Settings longer than a DES hash are bigcrypt.
+	long := "a password well over eight characters"
+	want, _ := BigCryptHash(long, "ab")
+	if got, err := Crypt(long, want); got != want {
+		t.Errorf("Crypt(%q, %q) = %v, %v, want %v", long, want, got, err, want)
+	}
+	if err := Verify(long, want); err != nil {
+		t.Errorf("Verify(%q, %q) error = %v", long, want, err)
+	}
+}
+
+func TestCryptErrors(t *testing.T) {
+	for _, setting := range []string{"$9$abc", "$"} {
+		if _, err := Crypt("password", setting); !errors.Is(err, ErrUnknownScheme) {
+			t.Errorf("Crypt(%q) error = %v, want ErrUnknownScheme", setting, err)
+		}
+		if err := Verify("password", setting); !errors.Is(err, ErrUnknownScheme) {
+			t.Errorf("Verify(%q) error = %v, want ErrUnknownScheme", setting, err)
+		}
+	}
+	if _, err := Crypt("password", "a"); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("Crypt(%q) error = %v, want ErrSaltTooShort", "a", err)
+	}
+
+	err := Verify("password", "{CRYPT}$1$a c$BXBqpb9BZcZhXLgbee.0s/")
+	var pe *ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, ErrMalformedHash) || pe.Pos != 11 || pe.Char != ' ' {
+		t.Errorf("Verify() error = %v, want ErrMalformedHash at 11", err)
+	}
+}
+
+// restoreSchemes undoes any RegisterScheme calls when t ends.
+func restoreSchemes(t *testing.T) {
+	schemesMu.RLock()
+	saved, savedRegistered := slices.Clone(schemes), maps.Clone(registered)
+	schemesMu.RUnlock()
+	t.Cleanup(func() {
+		schemesMu.Lock()
+		schemes, registered = saved, savedRegistered
+		schemesMu.Unlock()
+	})
+}
+
+func TestRegisterScheme(t *testing.T) {
+	restoreSchemes(t)
+	reverse := func(password, setting string) (string, error) {
+		b := []byte(password)
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return "$rev$" + string(b), nil
+	}
+	RegisterScheme(Scheme{
+		Name:   "reverse",
+		Prefix: "$rev$",
+		Hash:   reverse,
+		Verify: func(password, hash string) error {
+			if h, _ := reverse(password, hash); h != hash {
+				return ErrMismatch
+			}
+			return nil
+		},
+	})
+
+	if got, err := Crypt("abc", "$rev$"); got != "$rev$cba" || err != nil {
+		t.Errorf("Crypt() = %v, %v with registered scheme", got, err)
+	}
+	if err := Verify("abc", "{CRYPT}$rev$cba"); err != nil {
+		t.Errorf("Verify() error = %v with registered scheme", err)
+	}
+	if err := Verify("abd", "$rev$cba"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify() error = %v, want ErrMismatch", err)
+	}
+	if s, ok := lookupScheme("$5$salt"); !ok || !strings.HasPrefix(s.Name, "sha256") {
+		t.Errorf("lookupScheme($5$) = %v, %v", s.Name, ok)
+	}
+}
+
+func TestRegisterSchemeOverride(t *testing.T) {
+	restoreSchemes(t)
+	RegisterScheme(Scheme{
+		Name:   "hsm-sha512",
+		Prefix: sha512CryptPrefix,
+		Hash:   func(password, setting string) (string, error) { return setting + "$hsm", nil },
+		Verify: func(password, hash string) error { return nil },
+	})
+
+	setting := "$6$rounds=1000$saltstring"
+	if got, err := Crypt("pw", setting); got != setting+"$hsm" || err != nil {
+		t.Errorf("Crypt(%q) = %v, %v, want the override", setting, got, err)
+	}
+	// Everything that reads a setting must agree with Crypt on its scheme.
+	if info, err := Identify(setting + "$hsm"); err != nil || info.Scheme != "hsm-sha512" || info.Cost != 0 {
+		t.Errorf("Identify() = %+v, %v, want the override", info, err)
+	}
+	if got := CheckSalt(setting); got != SaltOK {
+		t.Errorf("CheckSalt(%q) = %v, want %v", setting, got, SaltOK)
+	}
+	if !NeedsRehash(setting+"$hsm", Policy{Scheme: "sha512crypt"}) {
+		t.Errorf("NeedsRehash() = false for a hash of another scheme")
+	}
+	SetMethodPolicy(MethodPolicy{Disabled: []string{"hsm-sha512"}})
+	defer SetMethodPolicy(MethodPolicy{})
+	if got := CheckSalt(setting); got != SaltMethodDisabled {
+		t.Errorf("CheckSalt(%q) = %v, want %v", setting, got, SaltMethodDisabled)
+	}
+	if _, err := Crypt("pw", setting); !errors.Is(err, ErrMethodDisabled) {
+		t.Errorf("Crypt(%q) error = %v, want ErrMethodDisabled", setting, err)
+	}
+}
@@ -0,0 +1,257 @@
+package descrypt
+
+// DES tables as published in FIPS 46, ported from crypt3.c. Entries number
+// bits from 1 at the most significant end of the input.
+var (
+	ipTable = [64]uint8{
+		58, 50, 42, 34, 26, 18, 10, 2,
+		60, 52, 44, 36, 28, 20, 12, 4,
+		62, 54, 46, 38, 30, 22, 14, 6,
+		64, 56, 48, 40, 32, 24, 16, 8,
+		57, 49, 41, 33, 25, 17, 9, 1,
+		59, 51, 43, 35, 27, 19, 11, 3,
+		61, 53, 45, 37, 29, 21, 13, 5,
+		63, 55, 47, 39, 31, 23, 15, 7,
+	}
+	fpTable = [64]uint8{
+		40, 8, 48, 16, 56, 24, 64, 32,
+		39, 7, 47, 15, 55, 23, 63, 31,
+		38, 6, 46, 14, 54, 22, 62, 30,
+		37, 5, 45, 13, 53, 21, 61, 29,
+		36, 4, 44, 12, 52, 20, 60, 28,
+		35, 3, 43, 11, 51, 19, 59, 27,
+		34, 2, 42, 10, 50, 18, 58, 26,
+		33, 1, 41, 9, 49, 17, 57, 25,
+	}
+	// pc1Table is PC1_C followed by PC1_D.
+	pc1Table = [56]uint8{
+		57, 49, 41, 33, 25, 17, 9,
+		1, 58, 50, 42, 34, 26, 18,
+		10, 2, 59, 51, 43, 35, 27,
+		19, 11, 3, 60, 52, 44, 36,
+
+		63, 55, 47, 39, 31, 23, 15,
+		7, 62, 54, 46, 38, 30, 22,
+		14, 6, 61, 53, 45, 37, 29,
+		21, 13, 5, 28, 20, 12, 4,
+	}
+	keyShifts = [16]uint8{1, 1, 2, 2, 2, 2, 2, 2, 1, 2, 2, 2, 2, 2, 2, 1}
+	// pc2Table is PC2_C followed by PC2_D, numbering the 56 bits of C||D.
+	pc2Table = [48]uint8{
+		14, 17, 11, 24, 1, 5,
+		3, 28, 15, 6, 21, 10,
+		23, 19, 12, 4, 26, 8,
+		16, 7, 27, 20, 13, 2,
+
+		41, 52, 31, 37, 47, 55,
+		30, 40, 51, 45, 33, 48,
+		44, 49, 39, 56, 34, 53,
+		46, 42, 50, 36, 29, 32,
+	}
+	eTable = [48]uint8{
+		32, 1, 2, 3, 4, 5,
+		4, 5, 6, 7, 8, 9,
+		8, 9, 10, 11, 12, 13,
+		12, 13, 14, 15, 16, 17,
+		16, 17, 18, 19, 20, 21,
+		20, 21, 22, 23, 24, 25,
+		24, 25, 26, 27, 28, 29,
+		28, 29, 30, 31, 32, 1,
+	}
+	sBoxes = [8][64]uint8{
+		{
+			14, 4, 13, 1, 2, 15, 11, 8, 3, 10, 6, 12, 5, 9, 0, 7,
+			0, 15, 7, 4, 14, 2, 13, 1, 10, 6, 12, 11, 9, 5, 3, 8,
+			4, 1, 14, 8, 13, 6, 2, 11, 15, 12, 9, 7, 3, 10, 5, 0,
+			15, 12, 8, 2, 4, 9, 1, 7, 5, 11, 3, 14, 10, 0, 6, 13,
+		},
+		{
+			15, 1, 8, 14, 6, 11, 3, 4, 9, 7, 2, 13, 12, 0, 5, 10,
+			3, 13, 4, 7, 15, 2, 8, 14, 12, 0, 1, 10, 6, 9, 11, 5,
+			0, 14, 7, 11, 10, 4, 13, 1, 5, 8, 12, 6, 9, 3, 2, 15,
+			13, 8, 10, 1, 3, 15, 4, 2, 11, 6, 7, 12, 0, 5, 14, 9,
+		},
+		{
+			10, 0, 9, 14, 6, 3, 15, 5, 1, 13, 12, 7, 11, 4, 2, 8,
+			13, 7, 0, 9, 3, 4, 6, 10, 2, 8, 5, 14, 12, 11, 15, 1,
+			13, 6, 4, 9, 8, 15, 3, 0, 11, 1, 2, 12, 5, 10, 14, 7,
+			1, 10, 13, 0, 6, 9, 8, 7, 4, 15, 14, 3, 11, 5, 2, 12,
+		},
+		{
+			7, 13, 14, 3, 0, 6, 9, 10, 1, 2, 8, 5, 11, 12, 4, 15,
+			13, 8, 11, 5, 6, 15, 0, 3, 4, 7, 2, 12, 1, 10, 14, 9,
+			10, 6, 9, 0, 12, 11, 7, 13, 15, 1, 3, 14, 5, 2, 8, 4,
+			3, 15, 0, 6, 10, 1, 13, 8, 9, 4, 5, 11, 12, 7, 2, 14,
+		},
+		{
+			2, 12, 4, 1, 7, 10, 11, 6, 8, 5, 3, 15, 13, 0, 14, 9,
+			14, 11, 2, 12, 4, 7, 13, 1, 5, 0, 15, 10, 3, 9, 8, 6,
+			4, 2, 1, 11, 10, 13, 7, 8, 15, 9, 12, 5, 6, 3, 0, 14,
+			11, 8, 12, 7, 1, 14, 2, 13, 6, 15, 0, 9, 10, 4, 5, 3,
+		},
+		{
+			12, 1, 10, 15, 9, 2, 6, 8, 0, 13, 3, 4, 14, 7, 5, 11,
+			10, 15, 4, 2, 7, 12, 9, 5, 6, 1, 13, 14, 0, 11, 3, 8,
+			9, 14, 15, 5, 2, 8, 12, 3, 7, 0, 4, 10, 1, 13, 11, 6,
+			4, 3, 2, 12, 9, 5, 15, 10, 11, 14, 1, 7, 6, 0, 8, 13,
+		},
+		{
+			4, 11, 2, 14, 15, 0, 8, 13, 3, 12, 9, 7, 5, 10, 6, 1,
+			13, 0, 11, 7, 4, 9, 1, 10, 14, 3, 5, 12, 2, 15, 8, 6,
+			1, 4, 11, 13, 12, 3, 7, 14, 10, 15, 6, 8, 0, 5, 9, 2,
+			6, 11, 13, 8, 1, 4, 10, 7, 9, 5, 0, 15, 14, 2, 3, 12,
+		},
+		{
+			13, 2, 8, 4, 6, 15, 11, 1, 10, 9, 3, 14, 5, 0, 12, 7,
+			1, 15, 13, 8, 10, 3, 7, 4, 12, 5, 6, 11, 0, 14, 9, 2,
+			7, 11, 4, 1, 9, 12, 14, 2, 0, 6, 10, 13, 15, 3, 5, 8,
+			2, 1, 14, 7, 4, 10, 8, 13, 15, 12, 9, 0, 3, 5, 6, 11,
+		},
+	}
+	pTable = [32]uint8{
+		16, 7, 20, 21,
+		29, 12, 28, 17,
+		1, 15, 23, 26,
+		5, 18, 31, 10,
+		2, 8, 24, 14,
+		32, 27, 3, 9,
+		19, 13, 30, 6,
+		22, 11, 4, 25,
+	}
+)
+
+// permTable holds byte-wise lookup tables for a fixed bit permutation, so
+// that permuting a 64-bit word costs eight loads instead of one step per bit.
+type permTable [8][256]uint64
+
+// Lookup tables derived from the DES tables above at package init.
+var (
+	ipPerm  *permTable
+	fpPerm  *permTable
+	pc1Perm *permTable
+	pc2Perm *permTable
+
+	// spe combines each S-box with the P permutation: spe[i][x] is the
+	// P-permuted 32-bit contribution of S-box i for the 6-bit input x.
+	spe [8][64]uint32
+)
+
+func init() {
+	ipPerm = newPermTable(ipTable[:], 64)
+	fpPerm = newPermTable(fpTable[:], 64)
+	pc1Perm = newPermTable(pc1Table[:], 64)
+	pc2Perm = newPermTable(pc2Table[:], 56)
+
+	for i := range sBoxes {
+		for x := 0; x < 64; x++ {
+			row := (x>>4)&2 | x&1
+			col := (x >> 1) & 15
+			f := uint64(sBoxes[i][row*16+col]) << (28 - 4*i)
+			var out uint32
+			for j, src := range pTable {
+				out |= uint32(f>>(32-src)&1) << (31 - j)
+			}
+			spe[i][x] = out
+		}
+	}
+}
+
+// newPermTable builds the lookup tables for the permutation tab of an
+// inBits-wide input, right-aligned in a uint64. Output bit j (counting
+// from the most significant of len(tab) bits) is input bit tab[j].
+func newPermTable(tab []uint8, inBits int) *permTable {
+	p := new(permTable)
+	for j, src := range tab {
+		in := uint(inBits) - uint(src)
+		out := uint64(1) << (len(tab) - 1 - j)
+		for v := 0; v < 256; v++ {
+			if v>>(in%8)&1 != 0 {
+				p[7-in/8][v] |= out
+			}
+		}
+	}
+	return p
+}
+
+func (p *permTable) apply(x uint64) uint64 {
+	return p[0][x>>56] | p[1][x>>48&0xff] | p[2][x>>40&0xff] | p[3][x>>32&0xff] |
+		p[4][x>>24&0xff] | p[5][x>>16&0xff] | p[6][x>>8&0xff] | p[7][x&0xff]
+}
+
+// keySchedule holds the 16 DES round keys, each split into the 24-bit
+// halves that meet the two halves of the expanded right block.
+type keySchedule struct {
+	l, r [16]uint32
+}
+
+// init derives the round keys from a 64-bit DES key (parity bits ignored).
+func (ks *keySchedule) init(key uint64) {
+	cd := pc1Perm.apply(key)
+	c, d := uint32(cd>>28), uint32(cd&0x0fffffff)
+	for i, s := range keyShifts {
+		c = (c<<s | c>>(28-s)) & 0x0fffffff
+		d = (d<<s | d>>(28-s)) & 0x0fffffff
+		k := pc2Perm.apply(uint64(c)<<28 | uint64(d))
+		ks.l[i] = uint32(k >> 24)
+		ks.r[i] = uint32(k & 0xffffff)
+	}
+}
+
+// encrypt runs count back-to-back DES encryptions of the block (l, r),
+// which is given and returned in initial-permutation order. Because FP
+// and IP cancel between encryptions, callers apply them only at the ends.
+// Bits set in saltMask swap the matching bits of the two 24-bit halves of
+// the E expansion, as crypt(3) does with its salt.
+func (ks *keySchedule) encrypt(l, r, saltMask uint32, count int) (uint32, uint32) {
+	for ; count > 0; count-- {
+		for i := 0; i < 16; i++ {
+			el := (r&0x00000001)<<23 | (r&0xf8000000)>>9 | (r&0x1f800000)>>11 |
+				(r&0x01f80000)>>13 | (r&0x001f8000)>>15
+			er := (r&0x0001f800)<<7 | (r&0x00001f80)<<5 | (r&0x000001f8)<<3 |
+				(r&0x0000001f)<<1 | r>>31
+			t := (el ^ er) & saltMask
+			el ^= t ^ ks.l[i]
+			er ^= t ^ ks.r[i]
+			f := spe[0][el>>18] | spe[1][el>>12&63] | spe[2][el>>6&63] | spe[3][el&63] |
+				spe[4][er>>18] | spe[5][er>>12&63] | spe[6][er>>6&63] | spe[7][er&63]
+			l, r = r, l^f
+		}
+		l, r = r, l
+	}
+	return l, r
+}
+
+// initialPermute applies IP to a 64-bit block and splits it into halves.
+func initialPermute(block uint64) (l, r uint32) {
+	block = ipPerm.apply(block)
+	return uint32(block >> 32), uint32(block)
+}
+
+// finalPermute joins the halves of a block and applies FP.
+func finalPermute(l, r uint32) uint64 {
+	return fpPerm.apply(uint64(l)<<32 | uint64(r))
+}
+
+// finalPermuteConstantTime is finalPermute computed bit by bit, without
+// lookups indexed by the block.
+func finalPermuteConstantTime(l, r uint32) uint64 {
+	x := uint64(l)<<32 | uint64(r)
+	var out uint64
+	for j, src := range fpTable {
+		out |= (x >> (64 - src) & 1) << (63 - j)
+	}
+	return out
+}
+
+// saltMask converts an n-bit crypt(3) salt into the E-box swap mask used by
+// encrypt: salt bit i swaps bit i of the first expanded half with bit i of
+// the second, counting from the most significant end.
+func saltMask(salt uint32, n int) uint32 {
+	var m uint32
+	for i := 0; i < n; i++ {
+		if salt>>i&1 != 0 {
+			m |= 0x800000 >> i
+		}
+	}
+	return m
+}
@@ -1,13 +1,13 @@
-package descryptcheck
+package descrypt
 
 import (
 	"errors"
-	"strings"
+	"testing"
 )
 
-// DESCryptHash computes the DES crypt(3) hash for a password and salt (2 chars) in pure Go
-// Returns a 13-character string (2-char salt + 11-char hash)
-func DESCryptHash(password, salt string) (string, error) {
+// referenceDESCryptHash is the original bit-per-byte port of crypt3.c that
+// DESCryptHash replaced. It is kept as an oracle for the table-driven core.
+func referenceDESCryptHash(password, salt string) (string, error) {
 	if len(salt) < 2 {
 		return "", errors.New("salt must be 2 characters")
 	}
@@ -148,11 +148,6 @@ func DESCryptHash(password, salt string) (string, error) {
 		}
 	)
 
-	// // Helper functions for bit operations
-	// bit := func(b byte, n int) byte {
-	// 	return (b >> uint(7-n)) & 1
-	// }
-
 	// Step 1: Break password into 64 bits (7 bits per char, 8 chars max)
 	var key [64]byte
 	for i := 0; i < 64; i++ {
@@ -295,22 +290,40 @@ func DESCryptHash(password, salt string) (string, error) {
 	return string(out[:]), nil
 }
 
-// DESPasswordVerify verifies a password against a traditional DES crypt hash (13 chars) using Go-native implementation
-// Returns nil if the password matches, or an error if not
-func DESPasswordVerify(inputPassword string, storedHash string) error {
-	if strings.HasPrefix(storedHash, "{CRYPT}") {
-		storedHash = storedHash[7:]
-	}
-	if len(storedHash) != 13 {
-		return errors.New("invalid DES crypt hash length (expected 13 chars)")
-	}
-	salt := storedHash[:2]
-	computed, err := DESCryptHash(inputPassword, salt)
-	if err != nil {
-		return err
+func TestDESCryptHashMatchesReferenceAllSalts(t *testing.T) {
+	passwords := []string{"", "a", "password", "SecretPassword123", "\x7f\xff\x80 ~", "12345678"}
+	for s := 0; s < 4096; s++ {
+		salt := string([]byte{itoa64[s&63], itoa64[s>>6]})
+		for _, pw := range passwords {
+			want, err := referenceDESCryptHash(pw, salt)
+			if err != nil {
+				t.Fatalf("referenceDESCryptHash(%q, %q) error = %v", pw, salt, err)
+			}
+			got, err := DESCryptHash(pw, salt)
+			if err != nil {
+				t.Fatalf("DESCryptHash(%q, %q) error = %v", pw, salt, err)
+			}
+			if got != want {
+				t.Fatalf("DESCryptHash(%q, %q) = %q, want %q", pw, salt, got, want)
+			}
+		}
 	}
-	if computed != storedHash {
-		return errors.New("password does not match hash")
+}
+
+func TestDESCryptHashMatchesReferencePasswords(t *testing.T) {
+	// Cover every byte value in every key position.
+	for pos := 0; pos < 9; pos++ {
+		for c := 0; c < 256; c++ {
+			pw := []byte("abcdefghij")
+			pw[pos] = byte(c)
+			want, _ := referenceDESCryptHash(string(pw), "Zz")
+			got, err := DESCryptHash(string(pw), "Zz")
+			if err != nil {
+				t.Fatalf("DESCryptHash(%q) error = %v", pw, err)
+			}
+			if got != want {
+				t.Fatalf("DESCryptHash(%q) = %q, want %q", pw, got, want)
+			}
+		}
 	}
-	return nil
 }
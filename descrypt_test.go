@@ -4,6 +4,13 @@ import (
 	"testing"
 )
 
+// Callers store these as function values, so their signatures must not
+// change.
+var (
+	_ func(string, string) (string, error) = DESCryptHash
+	_ func(string, string) error           = DESPasswordVerify
+)
+
 func TestDESPasswordVerify(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -167,3 +174,9 @@ func TestDESCryptAgainstStandardImplementation(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkDESCryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		DESCryptHash("SecretPassword123", "rq")
+	}
+}
@@ -82,3 +82,41 @@ func CDESPasswordVerify(inputPassword string, storedHash string) error {
 	}
 	return nil
 }
+
+// ErrCUnsupported is returned by CCrypt when the system crypt() rejects a
+// setting, typically because the hashing method is not compiled in.
+var ErrCUnsupported = errors.New("setting not supported by system crypt")
+
+// CCrypt calls the system crypt() with any setting string
+// Returns the full hash, or ErrCUnsupported if crypt() fails or returns a failure token
+func CCrypt(password, setting string) (string, error) {
+	cPassword := C.CString(password)
+	defer C.free(unsafe.Pointer(cPassword))
+
+	cSetting := C.CString(setting)
+	defer C.free(unsafe.Pointer(cSetting))
+
+	cResult := C.des_crypt(cPassword, cSetting)
+	if cResult == nil {
+		return "", ErrCUnsupported
+	}
+	result := C.GoString(cResult)
+	if strings.HasPrefix(result, "*") {
+		return "", ErrCUnsupported
+	}
+	return result, nil
+}
+
+// CGenSalt calls the system crypt_gensalt_rn() with the given random bytes
+// Returns the setting, or ErrCUnsupported if crypt_gensalt_rn() fails
+func CGenSalt(prefix string, count int, rbytes []byte) (string, error) {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+
+	var out [C.CRYPT_GENSALT_OUTPUT_SIZE]C.char
+	cResult := C.crypt_gensalt_rn(cPrefix, C.ulong(count), (*C.char)(unsafe.Pointer(&rbytes[0])), C.int(len(rbytes)), &out[0], C.int(len(out)))
+	if cResult == nil {
+		return "", ErrCUnsupported
+	}
+	return C.GoString(cResult), nil
+}
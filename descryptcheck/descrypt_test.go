@@ -1,7 +1,12 @@
 package descryptcheck
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/qoke/descrypt"
 )
 
 func TestCDESPasswordVerify(t *testing.T) {
@@ -90,12 +95,12 @@ func TestCDESPasswordVerify(t *testing.T) {
 
 		t.Run(tc.name+" (Go-native)", func(t *testing.T) {
 			if tc.malformed {
-				err := DESPasswordVerify(tc.inputPassword, tc.expectedHash)
+				err := descrypt.DESPasswordVerify(tc.inputPassword, tc.expectedHash)
 				if err == nil {
 					t.Errorf("DESPasswordVerify() should fail with malformed hash")
 				}
 
-				err = DESPasswordVerify(tc.inputPassword, tc.expectedHash+"X")
+				err = descrypt.DESPasswordVerify(tc.inputPassword, tc.expectedHash+"X")
 				if err == nil {
 					t.Errorf("DESPasswordVerify() should fail with malformed hash")
 				}
@@ -103,29 +108,29 @@ func TestCDESPasswordVerify(t *testing.T) {
 			}
 
 			if tc.empty {
-				err := DESPasswordVerify("", tc.expectedHash)
+				err := descrypt.DESPasswordVerify("", tc.expectedHash)
 				if err == nil {
 					t.Errorf("DESPasswordVerify() should fail with empty password")
 				}
 
-				err = DESPasswordVerify(tc.inputPassword, "")
+				err = descrypt.DESPasswordVerify(tc.inputPassword, "")
 				if err == nil {
 					t.Errorf("DESPasswordVerify() should fail with empty hash")
 				}
 				return
 			}
 
-			hash, err := DESCryptHash(tc.inputPassword, tc.salt)
+			hash, err := descrypt.DESCryptHash(tc.inputPassword, tc.salt)
 			if err != nil {
 				t.Fatalf("DESCryptHash() error = %v", err)
 			}
 
-			err = DESPasswordVerify(tc.inputPassword, hash)
+			err = descrypt.DESPasswordVerify(tc.inputPassword, hash)
 			if err != nil {
 				t.Errorf("DESPasswordVerify() failed for correct password: %v", err)
 			}
 
-			err = DESPasswordVerify(tc.wrongPassword, hash)
+			err = descrypt.DESPasswordVerify(tc.wrongPassword, hash)
 			if err == nil {
 				t.Errorf("DESPasswordVerify() should fail for wrong password")
 			}
@@ -140,7 +145,7 @@ func TestCDESPasswordVerify_Generated(t *testing.T) {
 	for _, pw := range passwords {
 		for _, salt := range salts {
 			hashC, errC := CdesCryptHash(pw, salt)
-			hashGo, errGo := DESCryptHash(pw, salt)
+			hashGo, errGo := descrypt.DESCryptHash(pw, salt)
 
 			if errC != nil {
 				t.Errorf("CdesCryptHash() error = %v for password '%s' salt '%s'", errC, pw, salt)
@@ -158,7 +163,7 @@ func TestCDESPasswordVerify_Generated(t *testing.T) {
 			if err != nil {
 				t.Errorf("CDESPasswordVerify() failed for generated hash: password='%s', salt='%s', hash='%s', err=%v", pw, salt, hashC, err)
 			}
-			err = DESPasswordVerify(pw, hashGo)
+			err = descrypt.DESPasswordVerify(pw, hashGo)
 			if err != nil {
 				t.Errorf("DESPasswordVerify() failed for generated hash: password='%s', salt='%s', hash='%s', err=%v", pw, salt, hashGo, err)
 			}
@@ -176,7 +181,7 @@ func TestCDESPasswordVerify_Generated(t *testing.T) {
 			}
 
 			wrongHashC, _ := CdesCryptHash(wrong, salt)
-			wrongHashGo, _ := DESCryptHash(wrong, salt)
+			wrongHashGo, _ := descrypt.DESCryptHash(wrong, salt)
 			if wrongHashC == hashC {
 				t.Errorf("CdesCryptHash() should generate different hashes for different passwords: password='%s', wrong='%s', hash='%s'", pw, wrong, hashC)
 			}
@@ -188,7 +193,7 @@ func TestCDESPasswordVerify_Generated(t *testing.T) {
 			if err == nil {
 				t.Errorf("CDESPasswordVerify() should have failed for wrong password: got nil error for password='%s', hash='%s'", wrong, hashC)
 			}
-			err = DESPasswordVerify(wrong, hashGo)
+			err = descrypt.DESPasswordVerify(wrong, hashGo)
 			if err == nil {
 				t.Errorf("DESPasswordVerify() should have failed for wrong password: got nil error for password='%s', hash='%s'", wrong, hashGo)
 			}
@@ -230,7 +235,7 @@ func TestDESCryptAgainstStandardImplementation(t *testing.T) {
 			}
 		})
 		t.Run(tc.password+" (Go-native)", func(t *testing.T) {
-			hash, err := DESCryptHash(tc.password, tc.salt)
+			hash, err := descrypt.DESCryptHash(tc.password, tc.salt)
 			if err != nil {
 				t.Fatalf("DESCryptHash() error = %v", err)
 			}
@@ -240,10 +245,303 @@ func TestDESCryptAgainstStandardImplementation(t *testing.T) {
 					hash, tc.expected, tc.password, tc.salt)
 			}
 
-			err = DESPasswordVerify(tc.password, tc.expected)
+			err = descrypt.DESPasswordVerify(tc.password, tc.expected)
 			if err != nil {
 				t.Errorf("DESPasswordVerify() failed for standard hash: %v", err)
 			}
 		})
 	}
 }
+
+func TestDESCryptHashBatchAgainstC(t *testing.T) {
+	passwords := []string{"SecretPassword123", "TestPassword123", "", "!@#$%^&*()", "short"}
+	for i := 0; i < 100; i++ {
+		passwords = append(passwords, fmt.Sprintf("batch%d", i*31))
+	}
+	salts := []string{"ab", "xy", "zz", "AA", "12", "./"}
+
+	for _, salt := range salts {
+		hashes, err := descrypt.DESCryptHashBatch(passwords, salt)
+		if err != nil {
+			t.Fatalf("DESCryptHashBatch() error = %v for salt '%s'", err, salt)
+		}
+		for i, pw := range passwords {
+			hashC, errC := CdesCryptHash(pw, salt)
+			if errC != nil {
+				t.Errorf("CdesCryptHash() error = %v for password '%s' salt '%s'", errC, pw, salt)
+				continue
+			}
+			if hashes[i] != hashC {
+				t.Errorf("C and batch hashes differ: password='%s', salt='%s', C='%s', batch='%s'", pw, salt, hashC, hashes[i])
+			}
+		}
+	}
+}
+
+func TestExtendedDESCryptHashAgainstC(t *testing.T) {
+	if _, err := CCrypt("password", "_J9..CCCC"); err != nil {
+		t.Skipf("system crypt() lacks extended DES: %v", err)
+	}
+
+	passwords := []string{"password", "", "12345678", "123456789", "a very long password indeed", "\x01\x7f\x80\xff"}
+	for i := 0; i < 50; i++ {
+		passwords = append(passwords, strings.Repeat(fmt.Sprintf("ext%d-", i), i%7))
+	}
+	settings := []string{"_J9..CCCC", "_/...zzzz", "_.../....", "_zz..SDiz", "_Az0.abcd", "_01..xyz."}
+
+	for _, setting := range settings {
+		for _, pw := range passwords {
+			hashC, errC := CCrypt(pw, setting)
+			if errC != nil {
+				t.Errorf("CCrypt() error = %v for password '%s' setting '%s'", errC, pw, setting)
+				continue
+			}
+			hash, err := descrypt.ExtendedDESCryptHash(pw, setting)
+			if err != nil {
+				t.Fatalf("ExtendedDESCryptHash() error = %v for setting '%s'", err, setting)
+			}
+			if hash != hashC {
+				t.Errorf("C and Go hashes differ: password='%s', setting='%s', C='%s', Go='%s'", pw, setting, hashC, hash)
+			}
+			if err := descrypt.ExtendedDESPasswordVerify(pw, hashC); err != nil {
+				t.Errorf("ExtendedDESPasswordVerify() error = %v for C hash '%s'", err, hashC)
+			}
+		}
+	}
+}
+
+func TestMD5CryptHashAgainstC(t *testing.T) {
+	passwords := []string{"password", "", "Hello world!", "a much longer password than sixteen bytes", "\x01\x7f\x80\xff"}
+	for i := 0; i < 50; i++ {
+		passwords = append(passwords, strings.Repeat(fmt.Sprintf("md5-%d.", i), i%9))
+	}
+	salts := []string{"", "x", "abc", "saltstri", "./09AZaz", "12345678"}
+
+	schemes := []struct {
+		prefix string
+		hash   func(password, setting string) (string, error)
+		verify func(inputPassword, storedHash string) error
+	}{
+		{"$1$", descrypt.MD5CryptHash, descrypt.MD5CryptPasswordVerify},
+		{"$apr1$", descrypt.APR1Hash, descrypt.APR1PasswordVerify},
+	}
+	for _, scheme := range schemes {
+		t.Run(scheme.prefix, func(t *testing.T) {
+			if _, err := CCrypt("password", scheme.prefix+"abc"); err != nil {
+				t.Skipf("system crypt() lacks %s: %v", scheme.prefix, err)
+			}
+			for _, salt := range salts {
+				setting := scheme.prefix + salt
+				for _, pw := range passwords {
+					hashC, errC := CCrypt(pw, setting)
+					if errC != nil {
+						t.Errorf("CCrypt() error = %v for password '%s' setting '%s'", errC, pw, setting)
+						continue
+					}
+					hash, err := scheme.hash(pw, setting)
+					if err != nil {
+						t.Fatalf("hash error = %v for setting '%s'", err, setting)
+					}
+					if hash != hashC {
+						t.Errorf("C and Go hashes differ: password='%s', setting='%s', C='%s', Go='%s'", pw, setting, hashC, hash)
+					}
+					if err := scheme.verify(pw, hashC); err != nil {
+						t.Errorf("verify error = %v for C hash '%s'", err, hashC)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSHACryptHashAgainstC(t *testing.T) {
+	passwords := []string{"password", "", "Hello world!", strings.Repeat("long password ", 10), "\x01\x7f\x80\xff"}
+	for i := 0; i < 30; i++ {
+		passwords = append(passwords, strings.Repeat(fmt.Sprintf("sha-%d.", i), i%11))
+	}
+	// libxcrypt rejects rather than clamps rounds outside [1000, 999999999],
+	// so only in-range counts are compared here.
+	// Salts may hold any byte but "$"; libxcrypt's crypt() refuses a few
+	// more, such as '!' and 8-bit bytes, for every scheme.
+	params := []string{"", "x", "saltstring", "./09AZaz", "0123456789abcdefXYZ", "rounds=1000$abc", "rounds=1234$saltstring", "rounds=5000$short",
+		"sa~lt{}", "#%&()-+=?@[]^_`|", "rounds=1000$a~b\"'<>,"}
+	// glibc reads a "rounds=" without a number and "$" as salt, and an
+	// empty number as 0; libxcrypt rejects both, so these are compared
+	// only where the system crypt() accepts them.
+	glibcOnly := map[string]bool{"rounds=12x$abc": true, "rounds=$abc": true, "rounds=5000": true}
+	for param := range glibcOnly {
+		params = append(params, param)
+	}
+
+	schemes := []struct {
+		prefix string
+		hash   func(password, setting string) (string, error)
+		verify func(inputPassword, storedHash string) error
+	}{
+		{"$5$", descrypt.SHA256CryptHash, descrypt.SHA256CryptPasswordVerify},
+		{"$6$", descrypt.SHA512CryptHash, descrypt.SHA512CryptPasswordVerify},
+	}
+	for _, scheme := range schemes {
+		t.Run(scheme.prefix, func(t *testing.T) {
+			if _, err := CCrypt("password", scheme.prefix+"abc"); err != nil {
+				t.Skipf("system crypt() lacks %s: %v", scheme.prefix, err)
+			}
+			for _, param := range params {
+				setting := scheme.prefix + param
+				for _, pw := range passwords {
+					hashC, errC := CCrypt(pw, setting)
+					if errC != nil && glibcOnly[param] {
+						continue
+					}
+					if errC != nil {
+						t.Errorf("CCrypt() error = %v for password '%s' setting '%s'", errC, pw, setting)
+						continue
+					}
+					hash, err := scheme.hash(pw, setting)
+					if err != nil {
+						t.Fatalf("hash error = %v for setting '%s'", err, setting)
+					}
+					if hash != hashC {
+						t.Errorf("C and Go hashes differ: password='%s', setting='%s', C='%s', Go='%s'", pw, setting, hashC, hash)
+					}
+					if err := scheme.verify(pw, hashC); err != nil {
+						t.Errorf("verify error = %v for C hash '%s'", err, hashC)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBcryptHashAgainstC(t *testing.T) {
+	if _, err := CCrypt("password", "$2b$04$abcdefghijklmnopqrstuu"); err != nil {
+		t.Skipf("system crypt() lacks bcrypt: %v", err)
+	}
+
+	passwords := []string{"password", "", "U*U", strings.Repeat("0123456789", 8), "\xa3", "\xff\xff\xa3", "1\xa3345", "\x01\x7f\x80\xff"}
+	for i := 0; i < 20; i++ {
+		passwords = append(passwords, strings.Repeat(fmt.Sprintf("bf-%d\xc3\xa9", i), i%13))
+	}
+	salts := []string{"abcdefghijklmnopqrstuu", "CCCCCCCCCCCCCCCCCCCCC.", "/OK.fbVrR/bpIqNJ5ianF.", "0123456789ABCDEFGHIJKz"}
+
+	for _, variant := range []string{"$2a$", "$2b$", "$2y$"} {
+		for _, salt := range salts {
+			setting := variant + "04$" + salt
+			for _, pw := range passwords {
+				hashC, errC := CCrypt(pw, setting)
+				if errC != nil {
+					t.Errorf("CCrypt() error = %v for password '%s' setting '%s'", errC, pw, setting)
+					continue
+				}
+				hash, err := descrypt.BcryptHash(pw, setting)
+				if err != nil {
+					t.Fatalf("BcryptHash() error = %v for setting '%s'", err, setting)
+				}
+				if hash != hashC {
+					t.Errorf("C and Go hashes differ: password='%s', setting='%s', C='%s', Go='%s'", pw, setting, hashC, hash)
+				}
+				if err := descrypt.BcryptPasswordVerify(pw, hashC); err != nil {
+					t.Errorf("BcryptPasswordVerify() error = %v for C hash '%s'", err, hashC)
+				}
+			}
+		}
+	}
+}
+
+func TestYescryptHashAgainstC(t *testing.T) {
+	if _, err := CCrypt("password", "$y$j9T$F5Jx5fExrKuPp53xLKQ..1"); err != nil {
+		t.Skipf("system crypt() lacks yescrypt: %v", err)
+	}
+
+	passwords := []string{"password", "", "Hello world!", strings.Repeat("long password ", 10), "\x01\x7f\x80\xff"}
+	for i := 0; i < 10; i++ {
+		passwords = append(passwords, strings.Repeat(fmt.Sprintf("y-%d.", i), i%7))
+	}
+	// Flavors "j" (read-write), "/" (write-once) and "." (classic
+	// scrypt), with and without p and t, small enough to run quickly,
+	// plus libxcrypt's default "j9T", which also takes the prehash path.
+	settings := []string{
+		"$y$j9T$F5Jx5fExrKuPp53xLKQ..1",
+		"$y$j5.$abcdefgh", "$y$j7/$", "$y$j5T$abcd", "$y$jA.$/.", "$y$j9T0..$abc.",
+		"$y$j6...$xyz0", "$y$j6./0$xyz0", "$y$j6.0/1$0123456789abcdefABCDE.",
+		"$y$/5.$abcdefgh", "$y$/6./.$xyz0", "$y$/6.0..$xyz0",
+		"$y$.5.$abcdefgh", "$y$.6...$xyz0",
+	}
+
+	for _, setting := range settings {
+		for _, pw := range passwords {
+			hashC, errC := CCrypt(pw, setting)
+			if errC != nil {
+				t.Errorf("CCrypt() error = %v for password '%s' setting '%s'", errC, pw, setting)
+				continue
+			}
+			hash, err := descrypt.YescryptHash(pw, setting)
+			if err != nil {
+				t.Fatalf("YescryptHash() error = %v for setting '%s'", err, setting)
+			}
+			if hash != hashC {
+				t.Errorf("C and Go hashes differ: password='%s', setting='%s', C='%s', Go='%s'", pw, setting, hashC, hash)
+			}
+			if err := descrypt.YescryptPasswordVerify(pw, hashC); err != nil {
+				t.Errorf("YescryptPasswordVerify() error = %v for C hash '%s'", err, hashC)
+			}
+		}
+	}
+}
+
+func TestGenerateSaltAgainstC(t *testing.T) {
+	testCases := []struct {
+		scheme, prefix string
+		costs          []int
+	}{
+		{"des", "", []int{0}},
+		{"extended-des", "_", []int{0, 1, 2, 726, 1<<24 - 1}},
+		{"md5crypt", "$1$", []int{0}},
+		{"sha256crypt", "$5$", []int{0, 1, 1000, 5000, 123456, 2000000000}},
+		{"sha512crypt", "$6$", []int{0, 1000, 999999999}},
+		{"bcrypt", "$2b$", []int{0, 4, 12, 31}},
+		{"yescrypt", "$y$", []int{0, 1, 2, 3, 5, 11}},
+	}
+	for i := 0; i < 20; i++ {
+		rbytes := make([]byte, 16)
+		for j := range rbytes {
+			rbytes[j] = byte(i*71 + j*13)
+		}
+		for _, tc := range testCases {
+			for _, cost := range tc.costs {
+				want, errC := CGenSalt(tc.prefix, cost, rbytes)
+				if errC != nil {
+					t.Skipf("system crypt_gensalt_rn() lacks %s: %v", tc.scheme, errC)
+				}
+				got, err := descrypt.GenerateSalt(tc.scheme, cost, bytes.NewReader(rbytes))
+				if err != nil || got != want {
+					t.Errorf("GenerateSalt(%q, %d) = %v, %v, C = %v", tc.scheme, cost, got, err, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDESCryptHashCompatAgainstC(t *testing.T) {
+	// C sees a password only up to its first NUL, and every libc drops
+	// the top bit of each byte.
+	passwords := []string{"pass\x00word", "\x00secret", "abc\x00\x00def", "\xf0\xe1\xf3\xf3", "12345678\x009"}
+	salts := []string{"ab", "./", "zZ"}
+	for _, compat := range []descrypt.Compat{descrypt.GlibcLegacy, descrypt.Musl, descrypt.FreeBSD} {
+		for _, salt := range salts {
+			for _, pw := range passwords {
+				hashC, errC := CCrypt(pw, salt)
+				if errC != nil {
+					t.Fatalf("CCrypt() error = %v for password %q salt '%s'", errC, pw, salt)
+				}
+				hash, err := descrypt.Crypt(pw, salt, descrypt.WithCompat(compat))
+				if err != nil || hash != hashC {
+					t.Errorf("C and compat %d hashes differ: password=%q, salt='%s', C='%s', Go='%s' (%v)", compat, pw, salt, hashC, hash, err)
+				}
+				if err := descrypt.Verify(pw, hashC, descrypt.WithCompat(compat)); err != nil {
+					t.Errorf("Verify() error = %v for C hash '%s' with compat %d", err, hashC, compat)
+				}
+			}
+		}
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/qoke/descrypt/descryptcheck
 
 go 1.23.6
+
+require github.com/qoke/descrypt v0.0.0
+
+replace github.com/qoke/descrypt => ../
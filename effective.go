@@ -0,0 +1,59 @@
+package descrypt
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrPasswordTooLong means a password is longer than the 8 characters
+// traditional DES crypt uses, under the RejectOverlong option.
+var ErrPasswordTooLong = errors.New("password longer than 8 characters")
+
+// desKeyLen is the number of password characters traditional DES crypt
+// uses.
+const desKeyLen = 8
+
+// EffectiveKey returns the part of password that traditional DES crypt
+// hashes: its first 8 bytes, padded with zeros, each with the top bit
+// cleared. Passwords with the same effective key have the same hash under
+// every salt.
+func EffectiveKey(password string) [8]byte {
+	var key [desKeyLen]byte
+	for i := 0; i < desKeyLen && i < len(password); i++ {
+		key[i] = password[i] & 0x7f
+	}
+	return key
+}
+
+// Truncated reports whether traditional DES crypt ignores any of
+// password: bytes after the eighth, or the top bit of a byte.
+func Truncated(password string) bool {
+	if len(password) > desKeyLen {
+		return true
+	}
+	for i := 0; i < len(password); i++ {
+		if password[i] >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+// CryptEquivalent reports whether a and b have the same effective key, so
+// that either would log in wherever the other does under traditional DES
+// crypt. The comparison is constant-time.
+func CryptEquivalent(a, b string) bool {
+	ka, kb := EffectiveKey(a), EffectiveKey(b)
+	return subtle.ConstantTimeCompare(ka[:], kb[:]) == 1
+}
+
+// RejectOverlong makes Crypt and Verify, for traditional DES, return
+// ErrPasswordTooLong for a password of more than 8 characters rather than
+// silently ignore the rest, so that a prefix of a long password cannot log
+// in. Under WithCompat the length is
+// counted up to the first NUL, as C sees it.
+func RejectOverlong() CryptOption {
+	return func(o *cryptOptions) {
+		o.rejectOverlong = true
+	}
+}
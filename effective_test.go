@@ -0,0 +1,87 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEffectiveKey(t *testing.T) {
+	testCases := []struct {
+		password  string
+		want      [8]byte
+		truncated bool
+	}{
+		{"", [8]byte{}, false},
+		{"abc", [8]byte{'a', 'b', 'c'}, false},
+		{"CorrectH", [8]byte{'C', 'o', 'r', 'r', 'e', 'c', 't', 'H'}, false},
+		{"CorrectHorse", [8]byte{'C', 'o', 'r', 'r', 'e', 'c', 't', 'H'}, true},
+		{"\xe1bc", [8]byte{'a', 'b', 'c'}, true},
+		{"ab\x00c", [8]byte{'a', 'b', 0, 'c'}, false},
+	}
+	for _, tc := range testCases {
+		if got := EffectiveKey(tc.password); got != tc.want {
+			t.Errorf("EffectiveKey(%q) = %q, want %q", tc.password, got, tc.want)
+		}
+		if got := Truncated(tc.password); got != tc.truncated {
+			t.Errorf("Truncated(%q) = %v, want %v", tc.password, got, tc.truncated)
+		}
+	}
+}
+
+func TestCryptEquivalent(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want bool
+	}{
+		{"CorrectHorse", "CorrectH", true},
+		{"CorrectHorse", "CorrectHorseBatteryStaple", true},
+		{"\xc3\xa1bc", "C!bc", true},
+		{"abc", "abc\x00", true},
+		{"abc", "abd", false},
+		{"CorrectHorse", "correctHorse", false},
+	}
+	for _, tc := range testCases {
+		if got := CryptEquivalent(tc.a, tc.b); got != tc.want {
+			t.Errorf("CryptEquivalent(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+		// Equivalence is exactly equality of hashes.
+		ha, _ := DESCryptHash(tc.a, "ab")
+		hb, _ := DESCryptHash(tc.b, "ab")
+		if (ha == hb) != tc.want {
+			t.Errorf("DESCryptHash(%q) == DESCryptHash(%q) is %v, want %v", tc.a, tc.b, ha == hb, tc.want)
+		}
+	}
+}
+
+func TestRejectOverlong(t *testing.T) {
+	hash, err := Crypt("CorrectH", "ab", RejectOverlong())
+	if err != nil {
+		t.Fatalf("Crypt() error = %v for an 8-character password", err)
+	}
+	if err := Verify("CorrectHorse", hash); err != nil {
+		t.Errorf("Verify() error = %v without RejectOverlong", err)
+	}
+	if err := Verify("CorrectHorse", hash, RejectOverlong()); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("Verify() error = %v, want ErrPasswordTooLong", err)
+	}
+	if err := Verify("CorrectHorse", "{CRYPT}"+hash, RejectOverlong()); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("Verify() error = %v with {CRYPT} prefix, want ErrPasswordTooLong", err)
+	}
+	if err := Verify("CorrectH", hash, RejectOverlong()); err != nil {
+		t.Errorf("Verify() error = %v for an 8-character password", err)
+	}
+	if _, err := Crypt("CorrectHorse", "ab", RejectOverlong()); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("Crypt() error = %v, want ErrPasswordTooLong", err)
+	}
+
+	// C sees the password only up to a NUL.
+	if err := Verify("CorrectH\x00orse", hash, RejectOverlong(), WithCompat(GlibcLegacy)); err != nil {
+		t.Errorf("Verify() error = %v with a NUL after 8 characters", err)
+	}
+	// Long passwords are fine for schemes that use them.
+	long := "CorrectHorseBatteryStaple"
+	big, _ := BigCryptHash(long, "ab")
+	if err := Verify(long, big, RejectOverlong()); err != nil {
+		t.Errorf("Verify() error = %v for a bigcrypt hash", err)
+	}
+}
@@ -0,0 +1,57 @@
+package descrypt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the hash and verify functions. Parse failures are
+// reported as a *ParseError wrapping one of them, so test for them with
+// errors.Is.
+var (
+	// ErrMismatch means the password does not match a well-formed hash.
+	ErrMismatch = errors.New("password does not match hash")
+
+	// ErrMalformedHash means a stored hash is not in the format of the
+	// scheme it was checked against: it has the wrong length, or a
+	// character outside the crypt(3) alphabet.
+	ErrMalformedHash = errors.New("malformed crypt hash")
+
+	// ErrInvalidSalt means a salt argument has a character outside the
+	// crypt(3) alphabet.
+	ErrInvalidSalt = errors.New("invalid character in salt")
+
+	// ErrSaltTooShort means a salt argument is shorter than the scheme
+	// requires, e.g. fewer than 2 characters for DESCryptHash.
+	ErrSaltTooShort = errors.New("salt too short")
+
+	// ErrInvalidCost means the iteration count in a setting is outside
+	// the range the scheme allows.
+	ErrInvalidCost = errors.New("iteration count out of range")
+)
+
+// ParseError describes where a salt or stored hash failed to parse.
+type ParseError struct {
+	// Pos is the byte offset of the offending character, or the length
+	// of the input if it ended early.
+	Pos int
+	// Char is the offending character, which may itself be a NUL, or 0
+	// if the input ended early.
+	Char byte
+	// EOF is set if the input ended early rather than at a bad character.
+	EOF bool
+	// Err is ErrMalformedHash, ErrInvalidSalt, ErrSaltTooShort or
+	// ErrInvalidCost.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.EOF {
+		return fmt.Sprintf("%v: input ends at position %d", e.Err, e.Pos)
+	}
+	return fmt.Sprintf("%v: %q at position %d", e.Err, e.Char, e.Pos)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,112 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		password string
+		hash     string
+		want     error
+		pos      int
+		char     byte
+	}{
+		{"match", "SecretPassword123", "rq/N3gSWdwWeA", nil, 0, 0},
+		{"mismatch", "WrongPassword", "rq/N3gSWdwWeA", ErrMismatch, 0, 0},
+		{"too short", "password", "ab1xQWzQ9Qf", ErrMalformedHash, 11, 0},
+		{"empty", "password", "", ErrMalformedHash, 0, 0},
+		{"too long", "password", "ab1xQWzQ9Qf8wX", ErrMalformedHash, 13, 'X'},
+		{"bad salt", "password", "a!1xQWzQ9Qf8w", ErrMalformedHash, 1, '!'},
+		{"bad hash char", "password", "ab1xQWz Q9Qf8", ErrMalformedHash, 7, ' '},
+		{"bad char in short hash", "password", "ab1x*", ErrMalformedHash, 4, '*'},
+		{"prefixed", "password", "{CRYPT}ab1x*WzQ9Qf8w", ErrMalformedHash, 11, '*'},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DESPasswordVerify(tc.password, tc.hash)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("DESPasswordVerify() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("DESPasswordVerify() error = %v, want %v", err, tc.want)
+			}
+			if tc.want == ErrMismatch {
+				return
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("DESPasswordVerify() error = %T, want *ParseError", err)
+			}
+			if pe.Pos != tc.pos || pe.Char != tc.char {
+				t.Errorf("ParseError at %d %q, want %d %q", pe.Pos, pe.Char, tc.pos, tc.char)
+			}
+			if errors.Is(err, ErrMismatch) {
+				t.Errorf("malformed hash error %v should not be ErrMismatch", err)
+			}
+		})
+	}
+}
+
+func TestSaltErrors(t *testing.T) {
+	testCases := []struct {
+		salt string
+		want error
+		pos  int
+		char byte
+	}{
+		{"", ErrSaltTooShort, 0, 0},
+		{"a", ErrSaltTooShort, 1, 0},
+		{"!a", ErrInvalidSalt, 0, '!'},
+		{"a\x80", ErrInvalidSalt, 1, 0x80},
+	}
+
+	for _, tc := range testCases {
+		_, err := DESCryptHash("password", tc.salt)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("DESCryptHash(%q) error = %v, want %v", tc.salt, err, tc.want)
+			continue
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("DESCryptHash(%q) error = %#v, want position %d char %q", tc.salt, err, tc.pos, tc.char)
+		}
+		if _, err := DESCryptHashBatch([]string{"x"}, tc.salt); !errors.Is(err, tc.want) {
+			t.Errorf("DESCryptHashBatch(%q) error = %v, want %v", tc.salt, err, tc.want)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	err := &ParseError{Pos: 1, Char: '!', Err: ErrInvalidSalt}
+	if got, want := err.Error(), `invalid character in salt: '!' at position 1`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	err = &ParseError{Pos: 1, EOF: true, Err: ErrSaltTooShort}
+	if got, want := err.Error(), `salt too short: input ends at position 1`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// A NUL in the input is a bad character, not the end of the input.
+	testCases := []struct {
+		hash string
+		want string
+	}{
+		{"rq\x00N3gSWdwWeA", `malformed crypt hash: '\x00' at position 2`},
+		{"{CRYPT}rq/N3gSWdwWe\x00", `malformed crypt hash: '\x00' at position 19`},
+		{"rq/N3gSWdwWe", `malformed crypt hash: input ends at position 12`},
+		{"$1$saltst\x00r$", `malformed crypt hash: '\x00' at position 9`},
+	}
+	for _, tc := range testCases {
+		err := Verify("SecretPassword123", tc.hash)
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("Verify(%q) error = %v, want %q", tc.hash, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package descrypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+// BSDi extended DES crypt, as emitted by FreeBSD, NetBSD and BSD/OS:
+// "_CCCCSSSS" followed by an 11-character hash. CCCC and SSSS are 24-bit
+// little-endian numbers, 6 bits per character, giving the number of DES
+// encryptions and the salt. Unlike traditional crypt(3), every character
+// of the password counts: characters beyond the eighth are folded into the
+// key 8 at a time.
+
+// extendedSettingLen is the length of "_CCCCSSSS"; a full hash adds 11.
+const extendedSettingLen = 9
+
+// ExtendedDESCryptHash computes the BSDi extended DES crypt(3) hash for a password and a
+// 9-character setting ("_" + 4-char count + 4-char salt); anything after the setting is ignored
+// Returns a 20-character string (9-char setting + 11-char hash)
+func ExtendedDESCryptHash(password, setting string) (string, error) {
+	if err := checkMethod("extended-des"); err != nil {
+		return "", err
+	}
+	count, salt, err := parseExtendedSetting(setting)
+	if err != nil {
+		return "", err
+	}
+	var out [extendedSettingLen + 11]byte
+	copy(out[:], setting[:extendedSettingLen])
+	encodeBlock(out[extendedSettingLen:], extendedDESCrypt(password, count, salt))
+	return string(out[:]), nil
+}
+
+// ExtendedDESPasswordVerify verifies a password against a BSDi extended DES crypt hash (20 chars)
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not an extended
+// DES hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func ExtendedDESPasswordVerify(inputPassword string, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	count, salt, err := parseExtendedSetting(hash)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	if pe = checkEncoded(hash, extendedSettingLen, extendedSettingLen+11); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	var got [extendedSettingLen + 11]byte
+	copy(got[:], hash[:extendedSettingLen])
+	encodeBlock(got[extendedSettingLen:], extendedDESCrypt(inputPassword, count, salt))
+	if subtle.ConstantTimeCompare(got[:], []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseExtendedSetting decodes the iteration count and 24-bit salt of an
+// extended DES setting.
+func parseExtendedSetting(setting string) (count, salt uint32, err error) {
+	switch {
+	case len(setting) == 0:
+		return 0, 0, &ParseError{Pos: 0, EOF: true, Err: ErrSaltTooShort}
+	case setting[0] != '_':
+		return 0, 0, &ParseError{Pos: 0, Char: setting[0], Err: ErrInvalidSalt}
+	}
+	for i := 1; i < extendedSettingLen; i++ {
+		if i >= len(setting) {
+			return 0, 0, &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		v := strings.IndexByte(itoa64, setting[i])
+		if v < 0 {
+			return 0, 0, &ParseError{Pos: i, Char: setting[i], Err: ErrInvalidSalt}
+		}
+		if i < 5 {
+			count |= uint32(v) << (6 * (i - 1))
+		} else {
+			salt |= uint32(v) << (6 * (i - 5))
+		}
+	}
+	if count == 0 {
+		return 0, 0, &ParseError{Pos: 1, Char: setting[1], Err: ErrInvalidCost}
+	}
+	return count, salt, nil
+}
+
+// extendedDESCrypt returns the extended DES hash block of password: count
+// encryptions of the zero block under the folded key and 24-bit salt.
+func extendedDESCrypt(password string, count, salt uint32) uint64 {
+	var ks keySchedule
+	extendedDESKey(&ks, password)
+	return finalPermute(ks.encrypt(0, 0, saltMask(salt, 24), int(count)))
+}
+
+// extendedDESKey sets up ks for a password of any length. The first 8
+// characters form the key as in desKey; for each further group of up to 8,
+// the key is encrypted with itself and the group is XORed into the result.
+func extendedDESKey(ks *keySchedule, password string) {
+	key := desKey(password)
+	ks.init(key)
+	for i := 8; i < len(password); i += 8 {
+		l, r := initialPermute(key)
+		key = finalPermute(ks.encrypt(l, r, 0, 1)) ^ desKey(password[i:])
+		ks.init(key)
+	}
+}
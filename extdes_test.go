@@ -0,0 +1,99 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+// Extended DES vectors produced by libxcrypt.
+var extendedDESTests = []struct {
+	password string
+	hash     string
+}{
+	{"password", "_J9..CCCC.MOp/ZbelpA"},
+	{"", "_J9..CCCCBeguG7nmIew"},
+	{"U*U*U*U*", "_J9..CCCCXBrJUJV154M"},
+	{"U*U***U", "_J9..CCCCXUhOBTXzaiE"},
+	{"a very long password indeed", "_J9..CCCCnxtVDknvq3g"},
+	{"multiple words of text", "_J9..SDizsNslAkXSQg2"},
+	{"multiple words of text", "_/...zzzzQZwIGVbc3UM"},
+	{"12345678", "_J9..SDizMs.27FHroLs"},
+	{"123456789", "_J9..SDizNuLhu4URquc"},
+	{"1234567812345678", "_J9..SDizy3V4hLMuDRs"},
+}
+
+func TestExtendedDESCryptHash(t *testing.T) {
+	for _, tc := range extendedDESTests {
+		got, err := ExtendedDESCryptHash(tc.password, tc.hash)
+		if err != nil {
+			t.Fatalf("ExtendedDESCryptHash() error = %v", err)
+		}
+		if got != tc.hash {
+			t.Errorf("ExtendedDESCryptHash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+	}
+}
+
+func TestExtendedDESPasswordVerify(t *testing.T) {
+	for _, tc := range extendedDESTests {
+		if err := ExtendedDESPasswordVerify(tc.password, tc.hash); err != nil {
+			t.Errorf("ExtendedDESPasswordVerify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := ExtendedDESPasswordVerify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("ExtendedDESPasswordVerify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := ExtendedDESPasswordVerify(tc.password+"x", tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("ExtendedDESPasswordVerify(%q, %q) error = %v, want ErrMismatch", tc.password+"x", tc.hash, err)
+		}
+	}
+}
+
+func TestExtendedDESErrors(t *testing.T) {
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"J9..CCCC.MOp/ZbelpA", 0, 'J'},
+		{"_J9..CC", 7, 0},
+		{"_J9..C!CC.MOp/ZbelpA", 6, '!'},
+		{"_....CCCC.MOp/ZbelpA", 1, '.'},
+		{"_J9..CCCC.MOp/Zbelp", 19, 0},
+		{"_J9..CCCC.MOp/Zbe*pA", 17, '*'},
+		{"_J9..CCCC.MOp/ZbelpAx", 20, 'x'},
+		{"{CRYPT}_J9..CCCC.MOp/Zbelp", 26, 0},
+	}
+	for _, tc := range testCases {
+		err := ExtendedDESPasswordVerify("password", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("ExtendedDESPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("ExtendedDESPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+
+	settings := []struct {
+		setting string
+		want    error
+	}{
+		{"_J9..CC", ErrSaltTooShort},
+		{"$J9..CCCC", ErrInvalidSalt},
+		{"_J9..CC C", ErrInvalidSalt},
+		{"_....CCCC", ErrInvalidCost},
+	}
+	for _, tc := range settings {
+		if _, err := ExtendedDESCryptHash("password", tc.setting); !errors.Is(err, tc.want) {
+			t.Errorf("ExtendedDESCryptHash(%q) error = %v, want %v", tc.setting, err, tc.want)
+		}
+	}
+}
+
+func BenchmarkExtendedDESCryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ExtendedDESCryptHash("password", "_J9..CCCC")
+	}
+}
@@ -0,0 +1,615 @@
+//go:build ignore
+
+// This program generates the boolean circuits for the DES S-boxes used by
+// the bitsliced engine: sbox_bitslice.go for the pure-Go backend and
+// sbox_amd64.s for the SSE2 and AVX2 backends. Run it with go generate.
+//
+// Each S-box output is built by Shannon decomposition over its six inputs,
+// with every intermediate function hash-consed by truth table so outputs
+// share gates. All 720 input orders are tried and the smallest circuit kept.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+)
+
+// sBoxes duplicates the table in des.go; the generator cannot import the
+// package it generates code for.
+var sBoxes = [8][64]uint8{
+	{
+		14, 4, 13, 1, 2, 15, 11, 8, 3, 10, 6, 12, 5, 9, 0, 7,
+		0, 15, 7, 4, 14, 2, 13, 1, 10, 6, 12, 11, 9, 5, 3, 8,
+		4, 1, 14, 8, 13, 6, 2, 11, 15, 12, 9, 7, 3, 10, 5, 0,
+		15, 12, 8, 2, 4, 9, 1, 7, 5, 11, 3, 14, 10, 0, 6, 13,
+	},
+	{
+		15, 1, 8, 14, 6, 11, 3, 4, 9, 7, 2, 13, 12, 0, 5, 10,
+		3, 13, 4, 7, 15, 2, 8, 14, 12, 0, 1, 10, 6, 9, 11, 5,
+		0, 14, 7, 11, 10, 4, 13, 1, 5, 8, 12, 6, 9, 3, 2, 15,
+		13, 8, 10, 1, 3, 15, 4, 2, 11, 6, 7, 12, 0, 5, 14, 9,
+	},
+	{
+		10, 0, 9, 14, 6, 3, 15, 5, 1, 13, 12, 7, 11, 4, 2, 8,
+		13, 7, 0, 9, 3, 4, 6, 10, 2, 8, 5, 14, 12, 11, 15, 1,
+		13, 6, 4, 9, 8, 15, 3, 0, 11, 1, 2, 12, 5, 10, 14, 7,
+		1, 10, 13, 0, 6, 9, 8, 7, 4, 15, 14, 3, 11, 5, 2, 12,
+	},
+	{
+		7, 13, 14, 3, 0, 6, 9, 10, 1, 2, 8, 5, 11, 12, 4, 15,
+		13, 8, 11, 5, 6, 15, 0, 3, 4, 7, 2, 12, 1, 10, 14, 9,
+		10, 6, 9, 0, 12, 11, 7, 13, 15, 1, 3, 14, 5, 2, 8, 4,
+		3, 15, 0, 6, 10, 1, 13, 8, 9, 4, 5, 11, 12, 7, 2, 14,
+	},
+	{
+		2, 12, 4, 1, 7, 10, 11, 6, 8, 5, 3, 15, 13, 0, 14, 9,
+		14, 11, 2, 12, 4, 7, 13, 1, 5, 0, 15, 10, 3, 9, 8, 6,
+		4, 2, 1, 11, 10, 13, 7, 8, 15, 9, 12, 5, 6, 3, 0, 14,
+		11, 8, 12, 7, 1, 14, 2, 13, 6, 15, 0, 9, 10, 4, 5, 3,
+	},
+	{
+		12, 1, 10, 15, 9, 2, 6, 8, 0, 13, 3, 4, 14, 7, 5, 11,
+		10, 15, 4, 2, 7, 12, 9, 5, 6, 1, 13, 14, 0, 11, 3, 8,
+		9, 14, 15, 5, 2, 8, 12, 3, 7, 0, 4, 10, 1, 13, 11, 6,
+		4, 3, 2, 12, 9, 5, 15, 10, 11, 14, 1, 7, 6, 0, 8, 13,
+	},
+	{
+		4, 11, 2, 14, 15, 0, 8, 13, 3, 12, 9, 7, 5, 10, 6, 1,
+		13, 0, 11, 7, 4, 9, 1, 10, 14, 3, 5, 12, 2, 15, 8, 6,
+		1, 4, 11, 13, 12, 3, 7, 14, 10, 15, 6, 8, 0, 5, 9, 2,
+		6, 11, 13, 8, 1, 4, 10, 7, 9, 5, 0, 15, 14, 2, 3, 12,
+	},
+	{
+		13, 2, 8, 4, 6, 15, 11, 1, 10, 9, 3, 14, 5, 0, 12, 7,
+		1, 15, 13, 8, 10, 3, 7, 4, 12, 5, 6, 11, 0, 14, 9, 2,
+		7, 11, 4, 1, 9, 12, 14, 2, 0, 6, 10, 13, 15, 3, 5, 8,
+		2, 1, 14, 7, 4, 10, 8, 13, 15, 12, 9, 0, 3, 5, 6, 11,
+	},
+}
+
+// pTable duplicates the P permutation from des.go.
+var pTable = [32]uint8{
+	16, 7, 20, 21,
+	29, 12, 28, 17,
+	1, 15, 23, 26,
+	5, 18, 31, 10,
+	2, 8, 24, 14,
+	32, 27, 3, 9,
+	19, 13, 30, 6,
+	22, 11, 4, 25,
+}
+
+type op int
+
+const (
+	opAnd op = iota
+	opOr
+	opXor
+	opAndNot // a &^ b
+)
+
+// gate computes node 6+i from nodes a and b. Nodes 0-5 are the inputs
+// a1-a6.
+type gate struct {
+	op   op
+	a, b int
+}
+
+// ref names a node or its complement. Complements are folded into the
+// gates that consume them, so the circuits need no NOT gates except
+// possibly on outputs.
+type ref struct {
+	n   int
+	neg bool
+}
+
+func (r ref) not() ref { return ref{r.n, !r.neg} }
+
+type circuit struct {
+	gates []gate
+	tts   []uint64       // truth table of every node
+	memo  map[uint64]ref // truth table -> node computing it
+	out   [4]ref
+}
+
+const all = ^uint64(0)
+
+// varTT is the truth table of input ai over the 64 input values, with a1
+// the most significant bit of the S-box index.
+func varTT(i int) uint64 {
+	var t uint64
+	for v := 0; v < 64; v++ {
+		if v>>(5-i)&1 != 0 {
+			t |= 1 << v
+		}
+	}
+	return t
+}
+
+func newCircuit() *circuit {
+	c := &circuit{memo: make(map[uint64]ref)}
+	for i := 0; i < 6; i++ {
+		c.tts = append(c.tts, varTT(i))
+		c.memo[varTT(i)] = ref{i, false}
+		c.memo[^varTT(i)] = ref{i, true}
+	}
+	return c
+}
+
+func (c *circuit) tt(r ref) uint64 {
+	if r.neg {
+		return ^c.tts[r.n]
+	}
+	return c.tts[r.n]
+}
+
+// gate returns a node computing t = op(a, b) for positive operands,
+// reusing an existing node when one already computes t or its complement.
+func (c *circuit) gate(o op, a, b int, t uint64, neg bool) ref {
+	if neg {
+		t = ^t
+	}
+	if r, ok := c.memo[t]; ok {
+		if neg {
+			return r.not()
+		}
+		return r
+	}
+	c.gates = append(c.gates, gate{o, a, b})
+	c.tts = append(c.tts, t)
+	n := len(c.tts) - 1
+	c.memo[t] = ref{n, false}
+	c.memo[^t] = ref{n, true}
+	if neg {
+		return ref{n, true}
+	}
+	return ref{n, false}
+}
+
+func (c *circuit) and(a, b ref) ref {
+	ta, tb := c.tt(a), c.tt(b)
+	switch {
+	case !a.neg && !b.neg:
+		return c.gate(opAnd, a.n, b.n, ta&tb, false)
+	case a.neg && !b.neg:
+		return c.gate(opAndNot, b.n, a.n, ta&tb, false)
+	case !a.neg && b.neg:
+		return c.gate(opAndNot, a.n, b.n, ta&tb, false)
+	}
+	return c.gate(opOr, a.n, b.n, ta&tb, true)
+}
+
+func (c *circuit) or(a, b ref) ref {
+	return c.and(a.not(), b.not()).not()
+}
+
+func (c *circuit) xor(a, b ref) ref {
+	return c.gate(opXor, a.n, b.n, c.tt(a)^c.tt(b), a.neg != b.neg)
+}
+
+// cofactors splits f on input v into its v=0 and v=1 halves, each
+// extended so that it no longer depends on v.
+func cofactors(f uint64, v int) (f0, f1 uint64) {
+	m := varTT(v)
+	s := uint(1) << (5 - v)
+	f0 = f &^ m
+	f0 |= f0 << s
+	f1 = f & m
+	f1 |= f1 >> s
+	return f0, f1
+}
+
+// build returns a node computing f, decomposing on inputs in order.
+// f must not be constant.
+func (c *circuit) build(f uint64, order []int) ref {
+	if r, ok := c.memo[f]; ok {
+		return r
+	}
+	for k, v := range order {
+		f0, f1 := cofactors(f, v)
+		if f0 == f1 {
+			continue
+		}
+		x, rest := ref{v, false}, order[k+1:]
+		switch {
+		case f0 == 0:
+			return c.and(c.build(f1, rest), x)
+		case f1 == 0:
+			return c.and(c.build(f0, rest), x.not())
+		case f0 == all:
+			return c.or(c.build(f1, rest), x.not())
+		case f1 == all:
+			return c.or(c.build(f0, rest), x)
+		case f0 == ^f1:
+			return c.xor(c.build(f0, rest), x)
+		}
+		// f = f0 ^ (x & (f0^f1)) = f1 ^ (^x & (f0^f1)); start from
+		// whichever cofactor already exists.
+		if _, ok := c.memo[f1]; ok {
+			if _, ok := c.memo[f0]; !ok {
+				return c.xor(c.build(f1, rest), c.and(c.build(f0^f1, rest), x.not()))
+			}
+		}
+		return c.xor(c.build(f0, rest), c.and(c.build(f0^f1, rest), x))
+	}
+	panic("constant function")
+}
+
+func outputTT(s, k int) uint64 {
+	var t uint64
+	for v := 0; v < 64; v++ {
+		row := (v>>4)&2 | v&1
+		col := (v >> 1) & 15
+		if sBoxes[s][row*16+col]>>(3-k)&1 != 0 {
+			t |= 1 << v
+		}
+	}
+	return t
+}
+
+func permutations(a []int, f func([]int)) {
+	var rec func(k int)
+	rec = func(k int) {
+		if k == len(a) {
+			f(a)
+			return
+		}
+		for i := k; i < len(a); i++ {
+			a[k], a[i] = a[i], a[k]
+			rec(k + 1)
+			a[k], a[i] = a[i], a[k]
+		}
+	}
+	rec(0)
+}
+
+func bestCircuit(s int) *circuit {
+	var best *circuit
+	permutations([]int{0, 1, 2, 3, 4, 5}, func(order []int) {
+		c := newCircuit()
+		for k := 0; k < 4; k++ {
+			c.out[k] = c.build(outputTT(s, k), order)
+		}
+		if best == nil || c.size() < best.size() {
+			best = c
+		}
+	})
+	return best
+}
+
+// size counts gates, including a NOT for each complemented output.
+func (c *circuit) size() int {
+	n := len(c.gates)
+	for _, o := range c.out {
+		if o.neg {
+			n++
+		}
+	}
+	return n
+}
+
+func name(n int) string {
+	if n < 6 {
+		return fmt.Sprintf("a%d", n+1)
+	}
+	return fmt.Sprintf("x%d", n-5)
+}
+
+func outName(r ref) string {
+	if r.neg {
+		return "^" + name(r.n)
+	}
+	return name(r.n)
+}
+
+func writeGo(circuits []*circuit) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_sbox.go; DO NOT EDIT.\n\npackage descrypt\n")
+	for s, c := range circuits {
+		fmt.Fprintf(&buf, "\n// s%d evaluates DES S-box %d as a %d-gate boolean circuit.\n", s+1, s+1, c.size())
+		fmt.Fprintf(&buf, "func s%d(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {\n", s+1)
+		for i, g := range c.gates {
+			dst := name(i + 6)
+			switch g.op {
+			case opAnd:
+				fmt.Fprintf(&buf, "%s := %s & %s\n", dst, name(g.a), name(g.b))
+			case opOr:
+				fmt.Fprintf(&buf, "%s := %s | %s\n", dst, name(g.a), name(g.b))
+			case opXor:
+				fmt.Fprintf(&buf, "%s := %s ^ %s\n", dst, name(g.a), name(g.b))
+			case opAndNot:
+				fmt.Fprintf(&buf, "%s := %s &^ %s\n", dst, name(g.a), name(g.b))
+			}
+		}
+		fmt.Fprintf(&buf, "return %s, %s, %s, %s\n}\n", outName(c.out[0]), outName(c.out[1]), outName(c.out[2]), outName(c.out[3]))
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("sbox_bitslice.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The assembly backends evaluate a whole round: for each S-box they gather
+// its six inputs from the right half and the key, run the circuit, and XOR
+// the outputs into the left half. Rows of the bitsliced arrays are 32 bytes
+// (bsWord); AVX2 handles a row at once, SSE2 in two 16-byte passes.
+
+const (
+	rowSize = 32
+	numRegs = 15 // register 15 is kept as a scratch register
+	never   = 1 << 30
+)
+
+type arch struct {
+	name  string
+	avx   bool
+	width int
+}
+
+func (a arch) reg(r int) string {
+	if a.avx {
+		return fmt.Sprintf("Y%d", r)
+	}
+	return fmt.Sprintf("X%d", r)
+}
+
+func (a arch) mov() string {
+	if a.avx {
+		return "VMOVDQU"
+	}
+	return "MOVOU"
+}
+
+// regAlloc assigns the nodes of one S-box circuit to vector registers,
+// spilling to stack slots by furthest next use (Belady's algorithm).
+type regAlloc struct {
+	a      arch
+	buf    *bytes.Buffer
+	c      *circuit
+	s      int
+	uses   [][]int // gate indices reading each node; outputs read at len(gates)
+	reg    []int   // node -> register, or -1
+	slot   []int   // node -> stack slot, or -1
+	owner  [numRegs]int
+	nslots int
+}
+
+func newRegAlloc(a arch, buf *bytes.Buffer, c *circuit, s int) *regAlloc {
+	n := len(c.gates) + 6
+	ra := &regAlloc{a: a, buf: buf, c: c, s: s, uses: make([][]int, n), reg: make([]int, n), slot: make([]int, n)}
+	for i, g := range c.gates {
+		ra.uses[g.a] = append(ra.uses[g.a], i)
+		ra.uses[g.b] = append(ra.uses[g.b], i)
+	}
+	for _, o := range c.out {
+		ra.uses[o.n] = append(ra.uses[o.n], len(c.gates))
+	}
+	for i := range ra.reg {
+		ra.reg[i], ra.slot[i] = -1, -1
+	}
+	for i := range ra.owner {
+		ra.owner[i] = -1
+	}
+	return ra
+}
+
+func (ra *regAlloc) emit(format string, args ...any) {
+	fmt.Fprintf(ra.buf, "\t"+format+"\n", args...)
+}
+
+func (ra *regAlloc) nextUse(n, i int) int {
+	for _, u := range ra.uses[n] {
+		if u >= i {
+			return u
+		}
+	}
+	return never
+}
+
+// alloc returns a free register at gate i, evicting the value whose next
+// use is furthest away. Registers in keep are not evicted.
+func (ra *regAlloc) alloc(i int, keep ...int) int {
+	best, bestUse := -1, -1
+	for r, n := range ra.owner {
+		if slices.Contains(keep, r) {
+			continue
+		}
+		if n < 0 || ra.nextUse(n, i) == never {
+			if n >= 0 {
+				ra.reg[n] = -1
+			}
+			ra.owner[r] = -1
+			return r
+		}
+		if u := ra.nextUse(n, i); u > bestUse {
+			best, bestUse = r, u
+		}
+	}
+	n := ra.owner[best]
+	if ra.slot[n] < 0 {
+		ra.slot[n] = ra.nslots
+		ra.nslots++
+		ra.emit("%s %s, %d(SP)", ra.a.mov(), ra.a.reg(best), ra.slot[n]*ra.a.width)
+	}
+	ra.reg[n] = -1
+	ra.owner[best] = -1
+	return best
+}
+
+func (ra *regAlloc) assign(n, r int) {
+	ra.reg[n] = r
+	ra.owner[r] = n
+}
+
+// load makes node n available in a register at gate i.
+func (ra *regAlloc) load(n, i int, keep ...int) int {
+	if r := ra.reg[n]; r >= 0 {
+		return r
+	}
+	r := ra.alloc(i, keep...)
+	if ra.slot[n] >= 0 {
+		ra.emit("%s %d(SP), %s", ra.a.mov(), ra.slot[n]*ra.a.width, ra.a.reg(r))
+	} else {
+		// First use of input n: r[e[j]] ^ k[kb[j]].
+		j := 6*ra.s + n
+		ra.emit("MOVBQZX %d(R8), AX", j)
+		ra.emit("SHLQ $5, AX")
+		ra.emit("MOVBQZX %d(R9), BX", j)
+		ra.emit("SHLQ $5, BX")
+		if ra.a.avx {
+			ra.emit("VMOVDQU (SI)(AX*1), %s", ra.a.reg(r))
+			ra.emit("VPXOR (DX)(BX*1), %s, %s", ra.a.reg(r), ra.a.reg(r))
+		} else {
+			ra.emit("MOVOU (SI)(AX*1), %s", ra.a.reg(r))
+			ra.emit("MOVOU (DX)(BX*1), X15")
+			ra.emit("PXOR X15, %s", ra.a.reg(r))
+		}
+	}
+	ra.assign(n, r)
+	return r
+}
+
+func (ra *regAlloc) dies(n, i int) bool { return ra.nextUse(n, i+1) == never }
+
+func (ra *regAlloc) gate(i int) {
+	g := ra.c.gates[i]
+	z := i + 6
+	rA := ra.load(g.a, i)
+	rB := ra.load(g.b, i, rA)
+	inst := map[op]string{opAnd: "AND", opOr: "OR", opXor: "XOR", opAndNot: "ANDN"}[g.op]
+
+	if ra.a.avx {
+		var d int
+		switch {
+		case ra.dies(g.a, i):
+			d = rA
+		case ra.dies(g.b, i):
+			d = rB
+		default:
+			d = ra.alloc(i+1, rA, rB)
+		}
+		if g.op == opAndNot {
+			// VPANDN computes ^src1 & src2: z = ^b & a.
+			ra.emit("VPANDN %s, %s, %s", ra.a.reg(rA), ra.a.reg(rB), ra.a.reg(d))
+		} else {
+			ra.emit("VP%s %s, %s, %s", inst, ra.a.reg(rB), ra.a.reg(rA), ra.a.reg(d))
+		}
+		ra.retarget(d, z)
+		return
+	}
+
+	// SSE2 instructions overwrite their destination operand, which for
+	// PANDN (dst = ^dst & src) must hold b.
+	var d, src int
+	switch {
+	case g.op != opAndNot && ra.dies(g.a, i):
+		d, src = rA, rB
+	case ra.dies(g.b, i):
+		d, src = rB, rA
+	case g.op != opAndNot:
+		d, src = ra.alloc(i+1, rA, rB), rB
+		ra.emit("MOVO %s, %s", ra.a.reg(rA), ra.a.reg(d))
+	default:
+		d, src = ra.alloc(i+1, rA, rB), rA
+		ra.emit("MOVO %s, %s", ra.a.reg(rB), ra.a.reg(d))
+	}
+	ra.emit("P%s %s, %s", inst, ra.a.reg(src), ra.a.reg(d))
+	ra.retarget(d, z)
+}
+
+// retarget records that register r now holds node z.
+func (ra *regAlloc) retarget(r, z int) {
+	if n := ra.owner[r]; n >= 0 {
+		ra.reg[n] = -1
+	}
+	ra.assign(z, r)
+}
+
+// outputs XORs the S-box outputs into the left half.
+func (ra *regAlloc) outputs(pbits [4]int) {
+	end := len(ra.c.gates)
+	for k, o := range ra.c.out {
+		r := ra.load(o.n, end)
+		reg, off := ra.a.reg(r), pbits[k]*rowSize
+		if ra.a.avx {
+			ra.emit("VPXOR %d(DI), %s, %s", off, reg, reg)
+			if o.neg {
+				ra.emit("VPCMPEQB Y15, Y15, Y15")
+				ra.emit("VPXOR Y15, %s, %s", reg, reg)
+			}
+			ra.emit("VMOVDQU %s, %d(DI)", reg, off)
+		} else {
+			ra.emit("MOVOU %d(DI), X15", off)
+			ra.emit("PXOR X15, %s", reg)
+			if o.neg {
+				ra.emit("PCMPEQB X15, X15")
+				ra.emit("PXOR X15, %s", reg)
+			}
+			ra.emit("MOVOU %s, %d(DI)", reg, off)
+		}
+		// The register no longer holds the node.
+		ra.reg[o.n] = -1
+		ra.owner[r] = -1
+	}
+}
+
+func writeAsm(circuits []*circuit) {
+	var pbits [8][4]int
+	for j, src := range pTable {
+		pbits[(src-1)/4][(src-1)%4] = j
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_sbox.go; DO NOT EDIT.\n\n//go:build amd64 && !purego\n\n#include \"textflag.h\"\n")
+	for _, a := range []arch{{"SSE2", false, 16}, {"AVX2", true, 32}} {
+		var body bytes.Buffer
+		frame := 0
+		for s, c := range circuits {
+			fmt.Fprintf(&body, "\n\t// S-box %d\n", s+1)
+			ra := newRegAlloc(a, &body, c, s)
+			for i := range c.gates {
+				ra.gate(i)
+			}
+			ra.outputs(pbits[s])
+			frame = max(frame, ra.nslots*a.width)
+		}
+
+		fmt.Fprintf(&buf, "\n// func bsRound%s(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)\n", a.name)
+		fmt.Fprintf(&buf, "TEXT ·bsRound%s(SB), NOSPLIT, $%d-48\n", a.name, frame)
+		fmt.Fprintf(&buf, "\tMOVQ l+0(FP), DI\n\tMOVQ r+8(FP), SI\n\tMOVQ k+16(FP), DX\n")
+		fmt.Fprintf(&buf, "\tMOVQ e+24(FP), R8\n\tMOVQ kb+32(FP), R9\n")
+		if a.avx {
+			buf.Write(body.Bytes())
+			fmt.Fprintf(&buf, "\n\tVZEROUPPER\n\tRET\n")
+			continue
+		}
+		// Two 16-byte passes cover the four words of a row; skip the
+		// second when only words 0-1 carry lanes.
+		fmt.Fprintf(&buf, "\tMOVQ words+40(FP), CX\n\tADDQ $1, CX\n\tSHRQ $1, CX\n\tJZ done\n\nloop:")
+		buf.Write(body.Bytes())
+		fmt.Fprintf(&buf, "\n\tADDQ $16, DI\n\tADDQ $16, SI\n\tADDQ $16, DX\n\tDECQ CX\n\tJNZ loop\n\ndone:\n\tRET\n")
+	}
+	if err := os.WriteFile("sbox_amd64.s", buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func main() {
+	var circuits []*circuit
+	total := 0
+	for s := 0; s < 8; s++ {
+		c := bestCircuit(s)
+		total += c.size()
+		circuits = append(circuits, c)
+	}
+	writeGo(circuits)
+	writeAsm(circuits)
+	log.Printf("%d gates", total)
+}
@@ -0,0 +1,150 @@
+package descrypt
+
+import (
+	"crypto/rand"
+	"io"
+	"strconv"
+)
+
+// Default costs GenerateSalt uses when passed a cost of 0, as in libxcrypt.
+const (
+	extendedDefaultCount = 725
+	bcryptDefaultCost    = 5
+	yescryptDefaultCost  = 5
+	yescryptMaxCost      = 11
+)
+
+// GenerateSalt returns a random setting for scheme, modeled on libxcrypt's
+// crypt_gensalt, that can be passed to Crypt or to the scheme's hash
+// function. scheme is a name as reported by Identify: "des", "bigcrypt",
+// "crypt16", "extended-des", "md5crypt", "apr1", "sha256crypt",
+// "sha512crypt", "bcrypt" or "yescrypt".
+//
+// A cost of 0 selects the scheme's default. Otherwise cost is the
+// iteration count for extended DES, made odd as BSD recommends; the rounds
+// for SHA-crypt, clamped to 1000..999999999; the base-2 logarithm of the
+// cost for bcrypt, 4..31; and for yescrypt a level from 1 to 11 that
+// doubles the memory used at each step. The DES schemes and MD5-crypt
+// have a fixed cost and accept only 0. An out-of-range cost returns
+// ErrInvalidCost and an unknown scheme ErrUnknownScheme.
+//
+// The salt is made from bytes read from rnd exactly as libxcrypt makes it
+// from its random bytes, so that each salt character, or for bcrypt and
+// yescrypt each of the 16 salt bytes, is uniform. A nil rnd uses
+// crypto/rand.
+func GenerateSalt(scheme string, cost int, rnd io.Reader) (string, error) {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	out := make([]byte, 0, 64)
+	n := 0 // number of random salt characters to append
+	switch scheme {
+	case "des", "bigcrypt", "crypt16":
+		if cost != 0 {
+			return "", ErrInvalidCost
+		}
+		n = 2
+	case "extended-des":
+		if cost == 0 {
+			cost = extendedDefaultCount
+		}
+		if cost < 0 || cost > 1<<24-1 {
+			return "", ErrInvalidCost
+		}
+		// Even counts weaken the hash of some keys.
+		cost |= 1
+		out = append(out, '_')
+		out = appendA64(out, uint32(cost), 4)
+		n = 4
+	case "md5crypt", "apr1":
+		if cost != 0 {
+			return "", ErrInvalidCost
+		}
+		prefix := md5CryptPrefix
+		if scheme == "apr1" {
+			prefix = apr1Prefix
+		}
+		out = append(out, prefix...)
+		n = md5CryptSaltMax
+	case "sha256crypt", "sha512crypt":
+		if cost < 0 {
+			return "", ErrInvalidCost
+		}
+		prefix := sha256CryptPrefix
+		if scheme == "sha512crypt" {
+			prefix = sha512CryptPrefix
+		}
+		out = append(out, prefix...)
+		if cost != 0 && cost != shaCryptRoundsDefault {
+			out = append(out, shaCryptRoundsPrefix...)
+			out = strconv.AppendInt(out, int64(max(shaCryptRoundsMin, min(cost, shaCryptRoundsMax))), 10)
+			out = append(out, '$')
+		}
+		n = shaCryptSaltMax
+	case "bcrypt":
+		if cost == 0 {
+			cost = bcryptDefaultCost
+		}
+		if cost < bcryptMinCost || cost > bcryptMaxCost {
+			return "", ErrInvalidCost
+		}
+		var salt [16]byte
+		if _, err := io.ReadFull(rnd, salt[:]); err != nil {
+			return "", err
+		}
+		out = append(out, "$2b$"...)
+		out = append(out, byte('0'+cost/10), byte('0'+cost%10), '$')
+		return string(appendBcrypt64(out, salt[:])), nil
+	case "yescrypt":
+		if cost == 0 {
+			cost = yescryptDefaultCost
+		}
+		if cost < 1 || cost > yescryptMaxCost {
+			return "", ErrInvalidCost
+		}
+		// libxcrypt's choice: 1 and 2 give 1 and 2 MiB with r = 8, and
+		// from 3 on r = 32 with N starting at 1024.
+		r, logN := uint32(32), uint32(cost+7)
+		if cost <= 2 {
+			r, logN = 8, uint32(cost+9)
+		}
+		var salt [16]byte
+		if _, err := io.ReadFull(rnd, salt[:]); err != nil {
+			return "", err
+		}
+		out = append(out, yescryptPrefix...)
+		out = appendYescryptUint32(out, (yescryptDefaults-yescryptRW)>>2+yescryptRW, 0)
+		out = appendYescryptUint32(out, logN, 1)
+		out = appendYescryptUint32(out, r, 1)
+		out = append(out, '$')
+		return string(appendYescrypt64(out, salt[:])), nil
+	default:
+		return "", ErrUnknownScheme
+	}
+
+	// As in libxcrypt, DES salt characters take the low 6 bits of a byte
+	// each, and longer salts are the encoding of 3 bytes per 4 characters.
+	// Either way every character is uniform over itoa64.
+	var salt [shaCryptSaltMax]byte
+	raw := salt[:n]
+	if n > 2 {
+		raw = salt[:n*3/4]
+	}
+	if _, err := io.ReadFull(rnd, raw); err != nil {
+		return "", err
+	}
+	if n == 2 {
+		return string(append(out, itoa64[raw[0]&63], itoa64[raw[1]&63])), nil
+	}
+	return string(appendYescrypt64(out, raw)), nil
+}
+
+// HashPassword computes the DES crypt(3) hash of password under a salt
+// chosen with crypto/rand.
+func HashPassword(password string) (string, error) {
+	salt, err := GenerateSalt("des", 0, nil)
+	if err != nil {
+		return "", err
+	}
+	return DESCryptHash(password, salt)
+}
@@ -0,0 +1,84 @@
+package descrypt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSalt(t *testing.T) {
+	rnd := []byte("0123456789abcdefghijklmnop")
+	testCases := []struct {
+		scheme string
+		cost   int
+		want   string
+	}{
+		{"des", 0, "kl"},
+		{"bigcrypt", 0, "kl"},
+		{"extended-des", 0, "_J9..k2XA"},
+		{"extended-des", 726, "_L9..k2XA"},
+		{"md5crypt", 0, "$1$k2XAnEHB"},
+		{"apr1", 0, "$apr1$k2XAnEHB"},
+		{"sha256crypt", 0, "$5$k2XAnEHBqQ1Ct2aM"},
+		{"sha256crypt", 5000, "$5$k2XAnEHBqQ1Ct2aM"},
+		{"sha512crypt", 10, "$6$rounds=1000$k2XAnEHBqQ1Ct2aM"},
+		{"bcrypt", 0, "$2b$05$KBCwKxOzLha2MUDgW0PjXe"},
+		{"bcrypt", 12, "$2b$12$KBCwKxOzLha2MUDgW0PjXe"},
+		{"yescrypt", 0, "$y$j9T$k2XAnEHBqQ1Ct2aMXFKNa/"},
+		{"yescrypt", 1, "$y$j75$k2XAnEHBqQ1Ct2aMXFKNa/"},
+	}
+	for _, tc := range testCases {
+		got, err := GenerateSalt(tc.scheme, tc.cost, bytes.NewReader(rnd))
+		if err != nil || got != tc.want {
+			t.Errorf("GenerateSalt(%q, %d) = %v, %v, want %v", tc.scheme, tc.cost, got, err, tc.want)
+			continue
+		}
+		if _, err := Crypt("password", got); err != nil {
+			t.Errorf("Crypt() error = %v for setting %q", err, got)
+		}
+	}
+}
+
+func TestGenerateSaltErrors(t *testing.T) {
+	testCases := []struct {
+		scheme string
+		cost   int
+		err    error
+	}{
+		{"des", 1, ErrInvalidCost},
+		{"md5crypt", 1000, ErrInvalidCost},
+		{"extended-des", 1 << 24, ErrInvalidCost},
+		{"sha256crypt", -1, ErrInvalidCost},
+		{"bcrypt", 3, ErrInvalidCost},
+		{"bcrypt", 32, ErrInvalidCost},
+		{"yescrypt", 12, ErrInvalidCost},
+		{"scrypt", 0, ErrUnknownScheme},
+	}
+	for _, tc := range testCases {
+		if _, err := GenerateSalt(tc.scheme, tc.cost, nil); !errors.Is(err, tc.err) {
+			t.Errorf("GenerateSalt(%q, %d) error = %v, want %v", tc.scheme, tc.cost, err, tc.err)
+		}
+	}
+	if _, err := GenerateSalt("sha512crypt", 0, strings.NewReader("short")); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("GenerateSalt() error = %v with short reader, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 8; i++ {
+		hash, err := HashPassword("SecretPassword123")
+		if err != nil {
+			t.Fatalf("HashPassword() error = %v", err)
+		}
+		if err := DESPasswordVerify("SecretPassword123", hash); err != nil {
+			t.Errorf("DESPasswordVerify() error = %v for %q", err, hash)
+		}
+		seen[hash[:2]] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("HashPassword() used the same salt every time: %v", seen)
+	}
+}
@@ -0,0 +1,123 @@
+package descrypt
+
+// Hasher holds the DES key schedule derived from one password, so the
+// password can be hashed under many salts or checked against many stored
+// hashes without repeating the key setup. A Hasher is safe for concurrent
+// use.
+type Hasher struct {
+	ks keySchedule
+
+	// bs holds the key in bitsliced form for a constant-time Hasher, which
+	// leaves ks unused.
+	bs *bsBatch
+}
+
+// HasherOption configures a Hasher.
+type HasherOption func(*hasherOptions)
+
+type hasherOptions struct {
+	constantTime bool
+}
+
+// ConstantTime makes the Hasher evaluate the DES S-boxes as boolean
+// circuits in the bitsliced engine instead of looking them up in tables.
+// No memory access then depends on the password, closing the cache-timing
+// side channel of the table lookups. Hashes are unchanged, but each takes
+// an order of magnitude longer to compute.
+func ConstantTime() HasherOption {
+	return func(o *hasherOptions) {
+		o.constantTime = true
+	}
+}
+
+// NewHasher derives the key schedule for password. As with DESCryptHash,
+// only the first 8 characters are significant.
+func NewHasher(password string, opts ...HasherOption) *Hasher {
+	var o hasherOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	h := new(Hasher)
+	if o.constantTime {
+		h.bs = new(bsBatch)
+		h.bs.setKey(0, desKey(password))
+	} else {
+		h.ks.init(desKey(password))
+	}
+	return h
+}
+
+// HashWithSalt computes the DES crypt(3) hash of the password under salt (2 chars)
+// Returns the same 13-character string as DESCryptHash
+func (h *Hasher) HashWithSalt(salt string) (string, error) {
+	if err := checkMethod("des"); err != nil {
+		return "", err
+	}
+	s, err := parseSalt(salt)
+	if err != nil {
+		return "", err
+	}
+	var out [13]byte
+	h.crypt(&out, [2]byte{salt[0], salt[1]}, s)
+	return string(out[:]), nil
+}
+
+// crypt writes the DES crypt(3) hash of the password to out, under the
+// 12-bit salt s whose characters are salt.
+func (h *Hasher) crypt(out *[13]byte, salt [2]byte, s uint32) {
+	if h.bs != nil {
+		h.cryptConstantTime(out, salt, s)
+		return
+	}
+	desCryptSchedule(out, &h.ks, salt, saltMask(s, 12))
+}
+
+// cryptConstantTime is crypt on the bitsliced engine, followed by a final
+// permutation and encoding that avoid table lookups as well.
+func (h *Hasher) cryptConstantTime(out *[13]byte, salt [2]byte, s uint32) {
+	var l, r bsHalf
+	h.bs.encrypt(&l, &r, saltContextOf(s).e, 25)
+	block := finalPermuteConstantTime(bsLane(&l, &r, 0))
+
+	out[0] = salt[0]
+	out[1] = salt[1]
+	for i := 0; i < 10; i++ {
+		out[2+i] = a64ConstantTime(block >> (58 - 6*i) & 63)
+	}
+	out[12] = a64ConstantTime(block << 2 & 63)
+}
+
+// Verify checks the password against a traditional DES crypt hash (13 chars)
+// Returns nil if the password matches, or an error if not
+// Like DESPasswordVerify, it takes the same time whatever the stored hash.
+func (h *Hasher) Verify(storedHash string) error {
+	pe, err := verifyDES(h, storedHash)
+	if err == ErrMalformedHash {
+		return &pe
+	}
+	return err
+}
+
+// VerifyAny checks the password against each of hashes in turn and returns
+// the index of the first one it matches. If none matches it returns -1 and
+// the mismatch error, or, when no hash was well-formed, the error Verify
+// gave for the first one.
+func (h *Hasher) VerifyAny(hashes []string) (int, error) {
+	var firstErr error
+	wellFormed := false
+	for i, hash := range hashes {
+		err := h.Verify(hash)
+		if err == nil {
+			return i, nil
+		}
+		if err == ErrMismatch {
+			wellFormed = true
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if wellFormed || firstErr == nil {
+		return -1, ErrMismatch
+	}
+	return -1, firstErr
+}
@@ -0,0 +1,108 @@
+package descrypt
+
+import "testing"
+
+func TestHasherHashWithSalt(t *testing.T) {
+	passwords := []string{"SecretPassword123", "", "abc123", "\xff\x01"}
+	salts := []string{"rq", "pn", "..", "zz", "9A"}
+
+	for _, pw := range passwords {
+		h := NewHasher(pw)
+		for _, salt := range salts {
+			want, _ := DESCryptHash(pw, salt)
+			got, err := h.HashWithSalt(salt)
+			if err != nil {
+				t.Fatalf("HashWithSalt() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("HashWithSalt() = %v, want %v for password '%s' and salt '%s'", got, want, pw, salt)
+			}
+		}
+	}
+
+	if _, err := NewHasher("x").HashWithSalt("a"); err == nil {
+		t.Errorf("HashWithSalt() should fail with short salt")
+	}
+	if _, err := NewHasher("x").HashWithSalt("a*"); err == nil {
+		t.Errorf("HashWithSalt() should fail with invalid salt")
+	}
+}
+
+func TestHasherVerifyAny(t *testing.T) {
+	h := NewHasher("SecretPassword123")
+	testCases := []struct {
+		name   string
+		hashes []string
+		index  int
+	}{
+		{"first", []string{"rq/N3gSWdwWeA", "pnA3klLBJ.CRU"}, 0},
+		{"second", []string{"pnA3klLBJ.CRU", "{CRYPT}rq/N3gSWdwWeA"}, 1},
+		{"after malformed", []string{"short", "rq/N3gSWdwWeA"}, 1},
+		{"none", []string{"pnA3klLBJ.CRU", "rqnO5.MEhjGLo"}, -1},
+		{"malformed only", []string{"short"}, -1},
+		{"empty", nil, -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			index, err := h.VerifyAny(tc.hashes)
+			if index != tc.index {
+				t.Errorf("VerifyAny() = %d, want %d", index, tc.index)
+			}
+			if (err == nil) != (tc.index >= 0) {
+				t.Errorf("VerifyAny() error = %v", err)
+			}
+		})
+	}
+
+	_, err := h.VerifyAny([]string{"short"})
+	if want := DESPasswordVerify("SecretPassword123", "short"); err.Error() != want.Error() {
+		t.Errorf("VerifyAny() error = %v, want %v", err, want)
+	}
+}
+
+func TestHasherConstantTime(t *testing.T) {
+	passwords := []string{"SecretPassword123", "", "abc123", "\xff\x01", "longerpassword"}
+	salts := []string{"rq", "..", "zz", "9A", "xy"}
+
+	for _, pw := range passwords {
+		h := NewHasher(pw, ConstantTime())
+		for _, salt := range salts {
+			want, _ := DESCryptHash(pw, salt)
+			got, err := h.HashWithSalt(salt)
+			if err != nil {
+				t.Fatalf("HashWithSalt() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("HashWithSalt() = %v, want %v for password '%s' and salt '%s'", got, want, pw, salt)
+			}
+			if err := h.Verify(want); err != nil {
+				t.Errorf("Verify() failed for correct password: %v", err)
+			}
+		}
+		if err := h.Verify("rqnO5.MEhjGLo"); err == nil {
+			t.Errorf("Verify() should fail for wrong password")
+		}
+	}
+}
+
+func TestConstantTimeHelpers(t *testing.T) {
+	for v := uint64(0); v < 64; v++ {
+		if got := a64ConstantTime(v); got != itoa64[v] {
+			t.Errorf("a64ConstantTime(%d) = %q, want %q", v, got, itoa64[v])
+		}
+	}
+	for _, x := range []uint64{0, 1, 0x0123456789abcdef, 0xfedcba9876543210, ^uint64(0)} {
+		l, r := uint32(x>>32), uint32(x)
+		if got, want := finalPermuteConstantTime(l, r), finalPermute(l, r); got != want {
+			t.Errorf("finalPermuteConstantTime(%#x) = %#x, want %#x", x, got, want)
+		}
+	}
+}
+
+func BenchmarkHasherConstantTime(b *testing.B) {
+	h := NewHasher("SecretPassword123", ConstantTime())
+	for i := 0; i < b.N; i++ {
+		h.HashWithSalt("rq")
+	}
+}
@@ -0,0 +1,197 @@
+package descrypt
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Info describes a stored hash, as reported by Identify.
+type Info struct {
+	// Scheme names the hashing scheme: "des", "extended-des",
+	// "bigcrypt", "md5crypt", "apr1", "sha256crypt",
+	// "sha512crypt", "bcrypt", "yescrypt", the name of a scheme added
+	// with RegisterScheme, or for LDAP digests "ldap-" and the lowercased
+	// scheme, e.g. "ldap-ssha".
+	Scheme string
+	// Alternatives names other schemes that produce hashes of the same
+	// form. A 24-character hash may be bigcrypt or crypt16.
+	Alternatives []string
+	// Salt is the salt as it appears in the hash, or in hex for LDAP
+	// salted digests.
+	Salt string
+	// Cost is the work factor the hash records: the iteration count for
+	// extended DES, the rounds for SHA-crypt, and the base-2 logarithm
+	// of the cost for bcrypt and of N for yescrypt. It is 0 for schemes
+	// with a fixed cost.
+	Cost int
+	// Legacy is set for schemes that are fast enough to brute-force and
+	// should not be used for new hashes: every DES variant, MD5-crypt,
+	// APR1 and the LDAP digests.
+	Legacy bool
+}
+
+// ldapDigests maps LDAP password scheme prefixes to their digest size;
+// the salted schemes append the salt to the digest.
+var ldapDigests = map[string]struct {
+	size   int
+	salted bool
+}{
+	"{MD5}":     {16, false},
+	"{SMD5}":    {16, true},
+	"{SHA}":     {20, false},
+	"{SSHA}":    {20, true},
+	"{SHA256}":  {32, false},
+	"{SSHA256}": {32, true},
+	"{SHA512}":  {64, false},
+	"{SSHA512}": {64, true},
+}
+
+// Identify reports the scheme and parameters of a stored hash without
+// checking any password against it. It recognizes every scheme Verify
+// handles, with or without a "{CRYPT}" prefix, and the LDAP {MD5}, {SMD5},
+// {SHA}, {SSHA}, {SHA256}, {SSHA256}, {SHA512} and {SSHA512} digests. It
+// returns ErrUnknownScheme for an unrecognized "$" or LDAP prefix, or a
+// *ParseError wrapping ErrMalformedHash if the hash is not well-formed.
+func Identify(hash string) (Info, error) {
+	if strings.HasPrefix(hash, "{") && !strings.HasPrefix(hash, "{CRYPT}") {
+		return identifyLDAP(hash)
+	}
+	h, offset := trimCryptPrefix(hash)
+	info, pe := identifyCrypt(h)
+	if pe != nil {
+		pe.Pos += offset
+		return Info{}, pe
+	}
+	if info.Scheme == "" {
+		return Info{}, ErrUnknownScheme
+	}
+	return info, nil
+}
+
+// identifyCrypt identifies a crypt(3) hash. It returns an empty Info for
+// an unknown "$" prefix.
+func identifyCrypt(h string) (Info, *ParseError) {
+	// As in Crypt, a registered scheme takes precedence.
+	if s, ok := lookupRegistered(h); ok {
+		return Info{Scheme: s.Name}, nil
+	}
+	var pe *ParseError
+	switch {
+	case strings.HasPrefix(h, "_"):
+		count, _, err := parseExtendedSetting(h)
+		if pe = asMalformed(err); pe != nil {
+			return Info{}, pe
+		}
+		if pe = checkEncoded(h, extendedSettingLen, extendedSettingLen+11); pe != nil {
+			return Info{}, pe
+		}
+		return Info{Scheme: "extended-des", Salt: h[5:extendedSettingLen], Cost: int(count), Legacy: true}, nil
+
+	case strings.HasPrefix(h, md5CryptPrefix), strings.HasPrefix(h, apr1Prefix):
+		info := Info{Scheme: "md5crypt", Legacy: true}
+		prefix := md5CryptPrefix
+		if strings.HasPrefix(h, apr1Prefix) {
+			info.Scheme, prefix = "apr1", apr1Prefix
+		}
+		salt, err := parseMD5Setting(h, prefix)
+		if pe = asMalformed(err); pe != nil {
+			return Info{}, pe
+		}
+		if pe = checkTail(h, len(prefix)+len(salt), md5CryptHashLen); pe != nil {
+			return Info{}, pe
+		}
+		info.Salt = salt
+		return info, nil
+
+	case strings.HasPrefix(h, sha256CryptPrefix), strings.HasPrefix(h, sha512CryptPrefix):
+		c := sha256Crypt
+		if strings.HasPrefix(h, sha512CryptPrefix) {
+			c = sha512Crypt
+		}
+		s, pe := c.parseHash(h)
+		if pe != nil {
+			return Info{}, pe
+		}
+		return Info{Scheme: c.name, Salt: s.salt, Cost: s.rounds}, nil
+
+	case strings.HasPrefix(h, "$2"):
+		_, cost, _, err := parseBcryptSetting(h)
+		if pe = asMalformed(err); pe != nil {
+			return Info{}, pe
+		}
+		if pe = checkEncoded(h, 7, bcryptSettingLen+bcryptHashLen); pe != nil {
+			return Info{}, pe
+		}
+		if c := h[bcryptSettingLen-1]; strings.IndexByte(bcryptAlphabet, c)&15 != 0 {
+			return Info{}, &ParseError{Pos: bcryptSettingLen - 1, Char: c, Err: ErrMalformedHash}
+		}
+		return Info{Scheme: "bcrypt", Salt: h[7:bcryptSettingLen], Cost: cost}, nil
+
+	case strings.HasPrefix(h, yescryptPrefix):
+		params, _, end, err := parseYescryptSetting(h)
+		if pe = asMalformed(err); pe != nil {
+			return Info{}, pe
+		}
+		if pe = checkTail(h, end, yescryptHashLen); pe != nil {
+			return Info{}, pe
+		}
+		start := strings.IndexByte(h[len(yescryptPrefix):], '$') + len(yescryptPrefix) + 1
+		return Info{Scheme: "yescrypt", Salt: h[start:end], Cost: int(params.logN)}, nil
+	}
+
+	if strings.HasPrefix(h, "$") {
+		return Info{}, nil
+	}
+
+	// Without a prefix, only the length tells the DES variants apart.
+	n := 13
+	if len(h) > n {
+		n += (len(h) - n + 10) / 11 * 11
+	}
+	if pe = checkEncoded(h, 0, n); pe != nil {
+		return Info{}, pe
+	}
+	info := Info{Scheme: "des", Salt: h[:2], Legacy: true}
+	switch {
+	case n == crypt16Len:
+		info.Scheme, info.Alternatives = "bigcrypt", []string{"crypt16"}
+	case n > 13:
+		info.Scheme = "bigcrypt"
+	}
+	return info, nil
+}
+
+// identifyLDAP identifies an LDAP digest such as "{SSHA}" followed by the
+// base64 encoding of the digest and any salt.
+func identifyLDAP(hash string) (Info, error) {
+	end := strings.IndexByte(hash, '}') + 1
+	d, ok := ldapDigests[hash[:end]]
+	if !ok {
+		return Info{}, ErrUnknownScheme
+	}
+	raw, err := base64.StdEncoding.DecodeString(hash[end:])
+	if e, ok := err.(base64.CorruptInputError); ok && end+int(e) < len(hash) {
+		pos := end + int(e)
+		return Info{}, &ParseError{Pos: pos, Char: hash[pos], Err: ErrMalformedHash}
+	} else if err != nil {
+		return Info{}, &ParseError{Pos: len(hash), EOF: true, Err: ErrMalformedHash}
+	}
+	if len(raw) < d.size || !d.salted && len(raw) > d.size || d.salted && len(raw) == d.size {
+		return Info{}, &ParseError{Pos: len(hash), EOF: true, Err: ErrMalformedHash}
+	}
+	return Info{
+		Scheme: "ldap-" + strings.ToLower(hash[1:end-1]),
+		Salt:   hex.EncodeToString(raw[d.size:]),
+		Legacy: true,
+	}, nil
+}
+
+// asMalformed turns a setting parse error into one wrapping
+// ErrMalformedHash, as reported for stored hashes.
+func asMalformed(err error) *ParseError {
+	if pe, ok := err.(*ParseError); ok {
+		return &ParseError{Pos: pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package descrypt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIdentify(t *testing.T) {
+	testCases := []struct {
+		hash string
+		want Info
+	}{
+		{"rq/N3gSWdwWeA", Info{Scheme: "des", Salt: "rq", Legacy: true}},
+		{"{CRYPT}rq/N3gSWdwWeA", Info{Scheme: "des", Salt: "rq", Legacy: true}},
+		{"_J9..CCCC.MOp/ZbelpA", Info{Scheme: "extended-des", Salt: "CCCC", Cost: 725, Legacy: true}},
+		{"abNANd4rxgLJ2Aj/mmIwA.Hw", Info{Scheme: "bigcrypt", Alternatives: []string{"crypt16"}, Salt: "ab", Legacy: true}},
+		{"abNANd4rxgLJ2Aj/mmIwA.HwAbcdefghijk", Info{Scheme: "bigcrypt", Salt: "ab", Legacy: true}},
+		{"$1$abc$BXBqpb9BZcZhXLgbee.0s/", Info{Scheme: "md5crypt", Salt: "abc", Legacy: true}},
+		{"$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0", Info{Scheme: "apr1", Salt: "qHDFfhPC", Legacy: true}},
+		{"$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", Info{Scheme: "sha256crypt", Salt: "saltstring", Cost: 5000}},
+		{"$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.", Info{Scheme: "sha512crypt", Salt: "saltstringsaltst", Cost: 10000}},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", Info{Scheme: "bcrypt", Salt: "abcdefghijklmnopqrstu.", Cost: 4}},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC", Info{Scheme: "yescrypt", Salt: "F5Jx5fExrKuPp53xLKQ..1", Cost: 12}},
+		{"{SSHA}uJDd0BIdJ9Z7yDCZNWdgYeb33+cBAgME", Info{Scheme: "ldap-ssha", Salt: "01020304", Legacy: true}},
+		{"{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=", Info{Scheme: "ldap-sha", Legacy: true}},
+		{"{MD5}Xr4ilOzQ4PCOq3aQ0qbuaQ==", Info{Scheme: "ldap-md5", Legacy: true}},
+	}
+	for _, tc := range testCases {
+		got, err := Identify(tc.hash)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Identify(%q) = %+v, %v, want %+v", tc.hash, got, err, tc.want)
+		}
+	}
+}
+
+func TestIdentifyErrors(t *testing.T) {
+	for _, hash := range []string{"$9$abc$def", "{CLEARTEXT}secret", "{SSHA"} {
+		if _, err := Identify(hash); !errors.Is(err, ErrUnknownScheme) {
+			t.Errorf("Identify(%q) error = %v, want ErrUnknownScheme", hash, err)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+	}{
+		{"", 0},
+		{"rq/N3gSWdwWe", 12},
+		{"{CRYPT}rq/N3gSWdwWe!", 19},
+		{"rq/N3gSWdwWeA12", 15},
+		{"_J9..CCCC.MOp/ZbelpAx", 20},
+		{"$1$abc$BXBqpb9BZcZhXLgbee.0s", 28},
+		{"$5$rounds=999$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", 10},
+		{"$2b$04$abcdefghijklmnopqrstuvutqifOaYVU3C7488gLW7DiF2.D.avTW", 28},
+		{"$2b$99$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", 4},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35r", 72},
+		{"{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ", 29},
+		{"{SSHA}5en6G6MezRroT3XKqkdPOmY/BfQ=", 34},
+		{"{MD5}Xr4ilOzQ4PCOq3a*0qbuaQ==", 20},
+	}
+	for _, tc := range testCases {
+		_, err := Identify(tc.hash)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrMalformedHash) || pe.Pos != tc.pos {
+			t.Errorf("Identify(%q) error = %v, want ErrMalformedHash at %d", tc.hash, err, tc.pos)
+		}
+	}
+}
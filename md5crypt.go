@@ -0,0 +1,162 @@
+package descrypt
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+// MD5-crypt, Poul-Henning Kamp's FreeBSD scheme, and Apache's $apr1$
+// variant, which differs only in its prefix: "$1$" or "$apr1$", a salt of
+// up to 8 characters, "$", and a 22-character hash of 1000 rounds of MD5.
+
+const (
+	md5CryptPrefix  = "$1$"
+	apr1Prefix      = "$apr1$"
+	md5CryptSaltMax = 8
+	md5CryptHashLen = 22
+)
+
+// MD5CryptHash computes the MD5-crypt hash for a password and a setting ("$1$" + salt of up to 8 chars)
+// Characters after the eighth of the salt, or after a "$" ending it, are ignored
+// Returns "$1$", the salt, "$" and a 22-character hash
+func MD5CryptHash(password, setting string) (string, error) {
+	return md5CryptHash(password, setting, "md5crypt", md5CryptPrefix)
+}
+
+// MD5CryptPasswordVerify verifies a password against an MD5-crypt hash ("$1$salt$hash")
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not an MD5-crypt
+// hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func MD5CryptPasswordVerify(inputPassword string, storedHash string) error {
+	return md5CryptVerify(inputPassword, storedHash, md5CryptPrefix)
+}
+
+// APR1Hash computes the Apache MD5 hash for a password and a setting ("$apr1$" + salt of up to 8 chars)
+// Returns "$apr1$", the salt, "$" and a 22-character hash
+func APR1Hash(password, setting string) (string, error) {
+	return md5CryptHash(password, setting, "apr1", apr1Prefix)
+}
+
+// APR1PasswordVerify verifies a password against an Apache MD5 hash ("$apr1$salt$hash")
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not an APR1 hash.
+// A "{CRYPT}" prefix is accepted. The final comparison is constant-time.
+func APR1PasswordVerify(inputPassword string, storedHash string) error {
+	return md5CryptVerify(inputPassword, storedHash, apr1Prefix)
+}
+
+func md5CryptHash(password, setting, scheme, prefix string) (string, error) {
+	if err := checkMethod(scheme); err != nil {
+		return "", err
+	}
+	salt, err := parseMD5Setting(setting, prefix)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, 0, len(prefix)+len(salt)+1+md5CryptHashLen)
+	out = append(out, setting[:len(prefix)+len(salt)]...)
+	out = append(out, '$')
+	return string(md5Crypt(out, password, salt, prefix)), nil
+}
+
+func md5CryptVerify(inputPassword, storedHash, prefix string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	salt, err := parseMD5Setting(hash, prefix)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	n := len(prefix) + len(salt)
+	if pe = checkTail(hash, n, md5CryptHashLen); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	got := make([]byte, 0, len(hash))
+	got = append(got, hash[:n+1]...)
+	got = md5Crypt(got, inputPassword, salt, prefix)
+	if subtle.ConstantTimeCompare(got, []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseMD5Setting returns the salt of an MD5-crypt style setting: up to 8
+// characters from itoa64 after prefix, ending at the first "$".
+func parseMD5Setting(setting, prefix string) (string, error) {
+	for i := 0; i < len(prefix); i++ {
+		if i >= len(setting) {
+			return "", &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		if setting[i] != prefix[i] {
+			return "", &ParseError{Pos: i, Char: setting[i], Err: ErrInvalidSalt}
+		}
+	}
+	salt := setting[len(prefix):]
+	for i := 0; i < len(salt) && i < md5CryptSaltMax; i++ {
+		if salt[i] == '$' {
+			return salt[:i], nil
+		}
+		if strings.IndexByte(itoa64, salt[i]) < 0 {
+			return "", &ParseError{Pos: len(prefix) + i, Char: salt[i], Err: ErrInvalidSalt}
+		}
+	}
+	return salt[:min(len(salt), md5CryptSaltMax)], nil
+}
+
+// md5Crypt appends the 22-character MD5-crypt hash of password under salt
+// and prefix to dst.
+func md5Crypt(dst []byte, password, salt, prefix string) []byte {
+	h := md5.New()
+	h.Write([]byte(password))
+	h.Write([]byte(salt))
+	h.Write([]byte(password))
+	var alt [md5.Size]byte
+	h.Sum(alt[:0])
+
+	h.Reset()
+	h.Write([]byte(password))
+	h.Write([]byte(prefix))
+	h.Write([]byte(salt))
+	for n := len(password); n > 0; n -= md5.Size {
+		h.Write(alt[:min(n, md5.Size)])
+	}
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write([]byte{0})
+		} else {
+			h.Write([]byte(password[:1]))
+		}
+	}
+	var sum [md5.Size]byte
+	h.Sum(sum[:0])
+
+	for i := 0; i < 1000; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write([]byte(password))
+		} else {
+			h.Write(sum[:])
+		}
+		if i%3 != 0 {
+			h.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			h.Write([]byte(password))
+		}
+		if i&1 != 0 {
+			h.Write(sum[:])
+		} else {
+			h.Write([]byte(password))
+		}
+		h.Sum(sum[:0])
+	}
+
+	for _, g := range [5][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		dst = appendA64(dst, uint32(sum[g[0]])<<16|uint32(sum[g[1]])<<8|uint32(sum[g[2]]), 4)
+	}
+	return appendA64(dst, uint32(sum[11]), 2)
+}
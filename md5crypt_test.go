@@ -0,0 +1,107 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+// MD5-crypt and APR1 vectors produced by libxcrypt and OpenSSL.
+var md5CryptTests = []struct {
+	password string
+	hash     string
+}{
+	{"password", "$1$abc$BXBqpb9BZcZhXLgbee.0s/"},
+	{"password", "$1$$I2o9Z7NcvQAKp7wyCTlia0"},
+	{"password", "$1$abcdefgh$G//4keteveJp0qb8z2DxG/"},
+	{"Hello world!", "$1$saltstri$YMyguxXMBpd2TEZ.vS/3q1"},
+	{"", "$1$$qRPK7m23GJusamGpoGLby/"},
+	{"a much longer password than sixteen bytes", "$1$12345678$yLppq.aqtfjKiej5RWDLq/"},
+	{"pw", "$1$x$1qCRnuKVJB0gRI72OR7wV1"},
+	{"myPassword", "$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0"},
+	{"", "$apr1$$J/S5FGXXjRRxbhIznTb/E1"},
+	{"a much longer password than sixteen bytes", "$apr1$12345678$RkSsHiO1RUdjaoav57ZaW/"},
+}
+
+func TestMD5CryptHash(t *testing.T) {
+	for _, tc := range md5CryptTests {
+		hash, verify := MD5CryptHash, MD5CryptPasswordVerify
+		if tc.hash[1] == 'a' {
+			hash, verify = APR1Hash, APR1PasswordVerify
+		}
+		got, err := hash(tc.password, tc.hash)
+		if err != nil {
+			t.Fatalf("hash(%q) error = %v", tc.hash, err)
+		}
+		if got != tc.hash {
+			t.Errorf("hash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+		if err := verify(tc.password, tc.hash); err != nil {
+			t.Errorf("verify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := verify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("verify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := verify(tc.password+"x", tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("verify(%q, %q) error = %v, want ErrMismatch", tc.password+"x", tc.hash, err)
+		}
+	}
+
+	for setting, want := range map[string]string{
+		"$1$abc":        "$1$abc$BXBqpb9BZcZhXLgbee.0s/",
+		"$1$abc$xyz":    "$1$abc$BXBqpb9BZcZhXLgbee.0s/",
+		"$1$abcdefghij": "$1$abcdefgh$G//4keteveJp0qb8z2DxG/",
+		"$1$":           "$1$$I2o9Z7NcvQAKp7wyCTlia0",
+	} {
+		if got, err := MD5CryptHash("password", setting); got != want {
+			t.Errorf("MD5CryptHash(%q) = %v, %v, want %v", setting, got, err, want)
+		}
+	}
+}
+
+func TestMD5CryptErrors(t *testing.T) {
+	settings := []struct {
+		setting string
+		want    error
+	}{
+		{"$1", ErrSaltTooShort},
+		{"$2$abc", ErrInvalidSalt},
+		{"$1$ab!d", ErrInvalidSalt},
+		{"$apr1$abc", ErrInvalidSalt},
+	}
+	for _, tc := range settings {
+		if _, err := MD5CryptHash("password", tc.setting); !errors.Is(err, tc.want) {
+			t.Errorf("MD5CryptHash(%q) error = %v, want %v", tc.setting, err, tc.want)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"$1$abc", 6, 0},
+		{"$1$abcdefghi$G//4keteveJp0qb8z2DxG/", 11, 'i'},
+		{"$1$abc$BXBqpb9BZcZhXLgbee.0s", 28, 0},
+		{"$1$abc$BXBqpb9BZcZhXLgbee.0s/x", 29, 'x'},
+		{"$1$abc$BXBqpb9BZ_ZhXLgbee.0s/", 16, '_'},
+		{"{CRYPT}$1$a c$BXBqpb9BZcZhXLgbee.0s/", 11, ' '},
+	}
+	for _, tc := range testCases {
+		err := MD5CryptPasswordVerify("password", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("MD5CryptPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("MD5CryptPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
+
+func BenchmarkMD5CryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		MD5CryptHash("password", "$1$abcdefgh")
+	}
+}
@@ -0,0 +1,161 @@
+package descrypt
+
+import (
+	"io"
+	"math/bits"
+)
+
+// Policy describes how new hashes should be made, so that stored hashes
+// made some other way can be found and replaced.
+type Policy struct {
+	// Scheme is the preferred scheme for new hashes, named as
+	// GenerateSalt takes it, e.g. "yescrypt". "crypt16" is not accepted,
+	// since Verify takes its hashes for bigcrypt.
+	Scheme string
+	// Cost is passed to GenerateSalt; 0 selects the scheme's default.
+	Cost int
+	// Rand is the source of salts; nil uses crypto/rand.
+	Rand io.Reader
+}
+
+// Hash computes the hash of password in the preferred scheme under a new
+// random salt. It returns ErrUnknownScheme for a "crypt16" policy.
+func (p Policy) Hash(password string) (string, error) {
+	setting, err := p.setting(p.Rand)
+	if err != nil {
+		return "", err
+	}
+	// Crypt cannot tell bigcrypt from DES by a bare salt.
+	if p.Scheme == "bigcrypt" {
+		return BigCryptHash(password, setting)
+	}
+	return Crypt(password, setting)
+}
+
+// setting returns a new setting under p, with salt read from rnd.
+func (p Policy) setting(rnd io.Reader) (string, error) {
+	if p.Scheme == "crypt16" {
+		return "", ErrUnknownScheme
+	}
+	return GenerateSalt(p.Scheme, p.Cost, rnd)
+}
+
+// zeroReader reads as an endless run of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+// NeedsRehash reports whether hash should be replaced by one made under
+// policy: it is malformed, in another scheme, or costs less to compute
+// than policy asks for. A policy Policy.Hash rejects never asks for a
+// rehash, since no replacement could be made.
+//
+// Hashes are told apart only by their form, so under a "bigcrypt" policy
+// a DES hash is current, being what bigcrypt makes of a short password.
+// UpgradingVerifier, which knows the password, still replaces it when the
+// password is longer.
+func NeedsRehash(hash string, policy Policy) bool {
+	want, err := policy.setting(zeroReader{})
+	if err != nil {
+		return false
+	}
+	info, err := Identify(hash)
+	if err != nil || !info.is(policy.Scheme) {
+		return true
+	}
+	h, _ := trimCryptPrefix(hash)
+	return settingWork(info.Scheme, h) < settingWork(info.Scheme, want)
+}
+
+// is reports whether a hash described by info could have been made in
+// scheme.
+func (info Info) is(scheme string) bool {
+	return info.Scheme == scheme || info.Scheme == "des" && scheme == "bigcrypt"
+}
+
+// settingWork returns a number that grows with the cost recorded in a
+// well-formed setting or hash of scheme, for comparing two of the same
+// scheme. It is 0 for schemes with a fixed cost, and for those added with
+// RegisterScheme, whose settings it cannot read.
+func settingWork(scheme, setting string) uint64 {
+	if _, ok := lookupRegistered(setting); ok {
+		return 0
+	}
+	switch scheme {
+	case "extended-des":
+		count, _, _ := parseExtendedSetting(setting)
+		return uint64(count)
+	case "sha256crypt":
+		s, _ := sha256Crypt.parseSetting(setting)
+		return uint64(s.rounds)
+	case "sha512crypt":
+		s, _ := sha512Crypt.parseSetting(setting)
+		return uint64(s.rounds)
+	case "bcrypt":
+		_, cost, _, _ := parseBcryptSetting(setting)
+		return 1 << cost
+	case "yescrypt":
+		// The memory used, N * r, times the passes over it, 1 + t.
+		y, _, _, _ := parseYescryptSetting(setting)
+		return uint64(1+y.t) << (y.logN + uint(bits.Len32(y.r)))
+	}
+	return 0
+}
+
+// UpgradingVerifier checks passwords against stored hashes of any scheme
+// Verify handles and, when a password matches a hash that NeedsRehash
+// rejects, such as a legacy DES crypt hash, hands a new hash of the
+// password under its policy to a callback to store in its place.
+type UpgradingVerifier struct {
+	policy  Policy
+	upgrade func(newHash string) error
+	report  func(error)
+}
+
+// NewUpgradingVerifier returns an UpgradingVerifier that makes new hashes
+// under policy and passes them to upgrade. If making or storing a new hash
+// fails, the error is passed to report, which may be nil to ignore it. It
+// panics if upgrade is nil.
+func NewUpgradingVerifier(policy Policy, upgrade func(newHash string) error, report func(error)) *UpgradingVerifier {
+	if upgrade == nil {
+		panic("descrypt: NewUpgradingVerifier with nil upgrade function")
+	}
+	return &UpgradingVerifier{policy: policy, upgrade: upgrade, report: report}
+}
+
+// Verify checks password against storedHash as Verify does. If the
+// password matches and storedHash needs a rehash, or under a "bigcrypt"
+// policy is a DES hash that dropped all but 8 characters of the password,
+// Verify calls the upgrade callback with the new hash before returning. A
+// failed upgrade goes to the report callback, not to the caller: the
+// password was correct, and storedHash is still valid, so Verify returns
+// nil on any match.
+func (v *UpgradingVerifier) Verify(password, storedHash string) error {
+	if err := Verify(password, storedHash); err != nil {
+		return err
+	}
+	if !NeedsRehash(storedHash, v.policy) && !v.truncated(password, storedHash) {
+		return nil
+	}
+	newHash, err := v.policy.Hash(password)
+	if err == nil {
+		err = v.upgrade(newHash)
+	}
+	if err != nil && v.report != nil {
+		v.report(err)
+	}
+	return nil
+}
+
+// truncated reports whether storedHash, matched by password, is a DES hash
+// that a bigcrypt policy would replace with one of the whole password.
+func (v *UpgradingVerifier) truncated(password, storedHash string) bool {
+	if v.policy.Scheme != "bigcrypt" || len(password) <= desKeyLen {
+		return false
+	}
+	info, err := Identify(storedHash)
+	return err == nil && info.Scheme == "des"
+}
@@ -0,0 +1,198 @@
+package descrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNeedsRehash(t *testing.T) {
+	sha := Policy{Scheme: "sha512crypt", Cost: 10000}
+	testCases := []struct {
+		hash   string
+		policy Policy
+		want   bool
+	}{
+		{"rq/N3gSWdwWeA", sha, true},
+		{"{CRYPT}rq/N3gSWdwWeA", sha, true},
+		{"rq/N3gSWdwWe", sha, true},
+		{"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", sha, true},
+		{"$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.", sha, false},
+		{"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", Policy{Scheme: "sha512crypt"}, false},
+		{"rq/N3gSWdwWeA", Policy{Scheme: "des"}, false},
+		{"_J9..CCCC.MOp/ZbelpA", Policy{Scheme: "extended-des", Cost: 1001}, true},
+		{"_J9..CCCC.MOp/ZbelpA", Policy{Scheme: "extended-des"}, false},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", Policy{Scheme: "bcrypt"}, true},
+		{"$2b$04$abcdefghijklmnopqrstu.utqifOaYVU3C7488gLW7DiF2.D.avTW", Policy{Scheme: "bcrypt", Cost: 4}, false},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC", Policy{Scheme: "yescrypt"}, false},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC", Policy{Scheme: "yescrypt", Cost: 6}, true},
+		{"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC", Policy{Scheme: "yescrypt", Cost: 2}, false},
+		{"rq/N3gSWdwWeA", Policy{Scheme: "bcrypt", Cost: 99}, false},
+	}
+	for _, tc := range testCases {
+		if got := NeedsRehash(tc.hash, tc.policy); got != tc.want {
+			t.Errorf("NeedsRehash(%q, %+v) = %v, want %v", tc.hash, tc.policy, got, tc.want)
+		}
+	}
+
+	// Identify cannot tell a short bigcrypt hash from DES, but what a
+	// policy makes must not need a rehash.
+	for _, scheme := range []string{"des", "bigcrypt", "extended-des", "md5crypt", "bcrypt"} {
+		policy := Policy{Scheme: scheme}
+		for _, pw := range []string{"secret", "a much longer secret"} {
+			hash, err := policy.Hash(pw)
+			if err != nil {
+				t.Fatalf("Policy{%q}.Hash() error = %v", scheme, err)
+			}
+			if NeedsRehash(hash, policy) {
+				t.Errorf("NeedsRehash(%q, %+v) = true right after Policy.Hash", hash, policy)
+			}
+		}
+	}
+}
+
+func TestUpgradingVerifier(t *testing.T) {
+	policy := Policy{Scheme: "sha256crypt", Rand: bytes.NewReader(make([]byte, 64))}
+	var upgraded []string
+	v := NewUpgradingVerifier(policy, func(newHash string) error {
+		upgraded = append(upgraded, newHash)
+		return nil
+	}, nil)
+
+	if err := v.Verify("wrong", "rq/N3gSWdwWeA"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify() error = %v for wrong password, want ErrMismatch", err)
+	}
+	if len(upgraded) != 0 {
+		t.Fatalf("upgrade called after a mismatch: %v", upgraded)
+	}
+
+	if err := v.Verify("SecretPassword123", "rq/N3gSWdwWeA"); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(upgraded) != 1 {
+		t.Fatalf("upgrade called %d times, want 1", len(upgraded))
+	}
+	newHash := upgraded[0]
+	if info, err := Identify(newHash); err != nil || info.Scheme != "sha256crypt" {
+		t.Errorf("Identify(%q) = %+v, %v, want sha256crypt", newHash, info, err)
+	}
+
+	// The new hash verifies without a further upgrade.
+	if err := v.Verify("SecretPassword123", newHash); err != nil {
+		t.Errorf("Verify() error = %v for upgraded hash", err)
+	}
+	if len(upgraded) != 1 {
+		t.Errorf("upgrade called again for a current hash: %v", upgraded)
+	}
+
+	// A failed upgrade must not fail the login.
+	errStore := errors.New("store failed")
+	var reported []error
+	v = NewUpgradingVerifier(policy, func(string) error { return errStore }, func(err error) {
+		reported = append(reported, err)
+	})
+	if err := v.Verify("SecretPassword123", "rq/N3gSWdwWeA"); err != nil {
+		t.Errorf("Verify() error = %v after a failed upgrade, want nil", err)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errStore) {
+		t.Errorf("reported %v, want the upgrade error", reported)
+	}
+	v = NewUpgradingVerifier(policy, func(string) error { return errStore }, nil)
+	if err := v.Verify("SecretPassword123", "rq/N3gSWdwWeA"); err != nil {
+		t.Errorf("Verify() error = %v after a failed upgrade, want nil", err)
+	}
+	if err := v.Verify("wrong", "rq/N3gSWdwWeA"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify() error = %v for wrong password, want ErrMismatch", err)
+	}
+}
+
+func TestUpgradingVerifierSchemes(t *testing.T) {
+	stored, err := DESCryptHash("passphrase", "rq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, scheme := range []string{"des", "bigcrypt", "crypt16", "extended-des", "md5crypt", "apr1", "sha256crypt", "sha512crypt", "bcrypt", "yescrypt"} {
+		t.Run(scheme, func(t *testing.T) {
+			var upgraded []string
+			v := NewUpgradingVerifier(Policy{Scheme: scheme}, func(newHash string) error {
+				upgraded = append(upgraded, newHash)
+				return nil
+			}, func(err error) {
+				t.Errorf("upgrade failed: %v", err)
+			})
+			if err := v.Verify("passphrase", stored); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if scheme == "des" || scheme == "crypt16" {
+				// crypt16 is never a policy target.
+				if len(upgraded) != 0 {
+					t.Fatalf("upgrade called for a current hash: %v", upgraded)
+				}
+				return
+			}
+			if len(upgraded) != 1 {
+				t.Fatalf("upgrade called %d times, want 1", len(upgraded))
+			}
+			newHash := upgraded[0]
+			if err := v.Verify("passphrase", newHash); err != nil {
+				t.Errorf("Verify(%q) error = %v for upgraded hash", newHash, err)
+			}
+			if err := v.Verify("wrong", newHash); !errors.Is(err, ErrMismatch) {
+				t.Errorf("Verify(%q) error = %v for wrong password, want ErrMismatch", newHash, err)
+			}
+			if len(upgraded) != 1 {
+				t.Errorf("upgrade called again for a current hash: %v", upgraded)
+			}
+		})
+	}
+}
+
+func TestUpgradingVerifierBigcrypt(t *testing.T) {
+	const password = "CorrectHorseBattery"
+	stored, err := DESCryptHash(password, "rq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var upgraded []string
+	v := NewUpgradingVerifier(Policy{Scheme: "bigcrypt"}, func(newHash string) error {
+		upgraded = append(upgraded, newHash)
+		return nil
+	}, nil)
+
+	// The DES hash ignores all but 8 characters, so it is replaced even
+	// though NeedsRehash, which lacks the password, counts it as current.
+	if err := v.Verify(password, stored); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(upgraded) != 1 {
+		t.Fatalf("upgrade called %d times, want 1", len(upgraded))
+	}
+	newHash := upgraded[0]
+	if len(newHash) != 35 {
+		t.Errorf("upgraded hash %q has length %d, want 35", newHash, len(newHash))
+	}
+	if err := v.Verify(password[:desKeyLen], newHash); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify() error = %v for the truncated password, want ErrMismatch", err)
+	}
+	if err := v.Verify(password, newHash); err != nil {
+		t.Errorf("Verify() error = %v for upgraded hash", err)
+	}
+	if len(upgraded) != 1 {
+		t.Errorf("upgrade called again for a current hash: %v", upgraded)
+	}
+}
+
+func TestNewUpgradingVerifierNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewUpgradingVerifier(nil upgrade) did not panic")
+		}
+	}()
+	NewUpgradingVerifier(Policy{Scheme: "sha512crypt"}, nil, nil)
+}
+
+func TestPolicyHashCrypt16(t *testing.T) {
+	if _, err := (Policy{Scheme: "crypt16"}).Hash("passphrase"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Policy{crypt16}.Hash() error = %v, want ErrUnknownScheme", err)
+	}
+}
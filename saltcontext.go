@@ -0,0 +1,99 @@
+package descrypt
+
+import "sync/atomic"
+
+// SaltContext holds the salt-dependent state for hashing under one
+// traditional 2-character salt: the E-box swap mask of the word core and
+// the E-box bit selection of the bitsliced engine. Contexts for the 4096
+// possible salts are built on first use and shared, so a SaltContext is
+// safe for concurrent use.
+type SaltContext struct {
+	salt [2]byte
+	mask uint32
+	e    *[48]uint8
+}
+
+// saltContexts caches the SaltContext for each 12-bit salt.
+var saltContexts [4096]atomic.Pointer[SaltContext]
+
+// SaltContextFor returns the shared SaltContext for salt (2 chars). Like
+// DESCryptHash it ignores characters after the first two.
+func SaltContextFor(salt string) (*SaltContext, error) {
+	if err := checkMethod("des"); err != nil {
+		return nil, err
+	}
+	s, err := parseSalt(salt)
+	if err != nil {
+		return nil, err
+	}
+	return saltContextOf(s), nil
+}
+
+// saltContextOf returns the shared SaltContext for the 12-bit salt s.
+func saltContextOf(s uint32) *SaltContext {
+	if ctx := saltContexts[s].Load(); ctx != nil {
+		return ctx
+	}
+	ctx := &SaltContext{
+		salt: [2]byte{itoa64[s&63], itoa64[s>>6]},
+		mask: saltMask(s, 12),
+		e:    bsExpansion(s),
+	}
+	if !saltContexts[s].CompareAndSwap(nil, ctx) {
+		ctx = saltContexts[s].Load()
+	}
+	return ctx
+}
+
+// Salt returns the 2-character salt of the context.
+func (ctx *SaltContext) Salt() string {
+	return string(ctx.salt[:])
+}
+
+// Hash computes the DES crypt(3) hash of password under the context's salt.
+// It returns the same 13-character string as DESCryptHash, or "" if the
+// method policy has since disabled "des"; callers must not store an empty
+// result as a hash.
+func (ctx *SaltContext) Hash(password string) string {
+	if methodDisabled("des") {
+		return ""
+	}
+	var out [13]byte
+	desCrypt(&out, desKey(password), ctx.salt, ctx.mask)
+	return string(out[:])
+}
+
+// HashBatch computes the DES crypt(3) hashes of passwords under the
+// context's salt with the bitsliced engine, as DESCryptHashBatch does. It
+// returns nil only if the method policy has since disabled "des"; with no
+// passwords it returns an empty, non-nil slice.
+func (ctx *SaltContext) HashBatch(passwords []string) []string {
+	if methodDisabled("des") {
+		return nil
+	}
+	return ctx.hashBatch(passwords)
+}
+
+// hashBatch is HashBatch without the method policy check.
+func (ctx *SaltContext) hashBatch(passwords []string) []string {
+	hashes := make([]string, 0, len(passwords))
+	for len(passwords) > 0 {
+		n := min(len(passwords), bsLanes)
+		var b bsBatch
+		for i, pw := range passwords[:n] {
+			b.setKey(i, desKey(pw))
+		}
+		var l, r bsHalf
+		b.encrypt(&l, &r, ctx.e, 25)
+
+		var out [13]byte
+		out[0] = ctx.salt[0]
+		out[1] = ctx.salt[1]
+		for i := 0; i < n; i++ {
+			encodeBlock(out[2:], finalPermute(bsLane(&l, &r, i)))
+			hashes = append(hashes, string(out[:]))
+		}
+		passwords = passwords[n:]
+	}
+	return hashes
+}
@@ -0,0 +1,79 @@
+package descrypt
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSaltContextFor(t *testing.T) {
+	for s := 0; s < 4096; s++ {
+		salt := string([]byte{itoa64[s&63], itoa64[s>>6]})
+		ctx, err := SaltContextFor(salt)
+		if err != nil {
+			t.Fatalf("SaltContextFor(%q) error = %v", salt, err)
+		}
+		if ctx.Salt() != salt {
+			t.Errorf("SaltContextFor(%q).Salt() = %q", salt, ctx.Salt())
+		}
+		again, _ := SaltContextFor(salt + "ignored")
+		if again != ctx {
+			t.Errorf("SaltContextFor(%q) returned a new context on second call", salt)
+		}
+	}
+
+	if _, err := SaltContextFor("a"); err == nil {
+		t.Errorf("SaltContextFor() should fail with short salt")
+	}
+	if _, err := SaltContextFor("{}"); err == nil {
+		t.Errorf("SaltContextFor() should fail with invalid salt")
+	}
+}
+
+func TestSaltContextHash(t *testing.T) {
+	passwords := []string{"SecretPassword123", "TestPassword123", "", "abc123", "\x80\xff"}
+	for _, salt := range []string{"rq", "pn", "xy", "./"} {
+		ctx, err := SaltContextFor(salt)
+		if err != nil {
+			t.Fatalf("SaltContextFor() error = %v", err)
+		}
+		batch := ctx.HashBatch(passwords)
+		for i, pw := range passwords {
+			want, _ := DESCryptHash(pw, salt)
+			if got := ctx.Hash(pw); got != want {
+				t.Errorf("Hash() = %v, want %v for password '%s' and salt '%s'", got, want, pw, salt)
+			}
+			if batch[i] != want {
+				t.Errorf("HashBatch()[%d] = %v, want %v for password '%s' and salt '%s'", i, batch[i], want, pw, salt)
+			}
+		}
+	}
+}
+
+func TestSaltContextConcurrent(t *testing.T) {
+	const salt = "Kq"
+	want, _ := DESCryptHash("concurrent", salt)
+
+	var wg sync.WaitGroup
+	contexts := make([]*SaltContext, 16)
+	for i := range contexts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, err := SaltContextFor(salt)
+			if err != nil {
+				t.Errorf("SaltContextFor() error = %v", err)
+				return
+			}
+			contexts[i] = ctx
+			if got := ctx.Hash("concurrent"); got != want {
+				t.Errorf("Hash() = %v, want %v", got, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, ctx := range contexts[1:] {
+		if ctx != contexts[0] {
+			t.Errorf("SaltContextFor() returned different contexts for the same salt")
+		}
+	}
+}
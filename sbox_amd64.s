@@ -0,0 +1,2392 @@
+// Code generated by gen_sbox.go; DO NOT EDIT.
+
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func bsRoundSSE2(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)
+TEXT ·bsRoundSSE2(SB), NOSPLIT, $96-48
+	MOVQ l+0(FP), DI
+	MOVQ r+8(FP), SI
+	MOVQ k+16(FP), DX
+	MOVQ e+24(FP), R8
+	MOVQ kb+32(FP), R9
+	MOVQ words+40(FP), CX
+	ADDQ $1, CX
+	SHRQ $1, CX
+	JZ done
+
+loop:
+	// S-box 1
+	MOVBQZX 5(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 5(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 4(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 4(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	PXOR X1, X2
+	MOVBQZX 1(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 1(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X2, X4
+	PXOR X3, X4
+	MOVBQZX 2(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 2(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X5
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X5
+	MOVO X3, X6
+	PANDN X5, X6
+	PXOR X4, X6
+	MOVO X0, X7
+	PAND X1, X7
+	MOVO X4, X8
+	PANDN X5, X8
+	PXOR X7, X8
+	MOVBQZX 3(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 3(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X9
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X9
+	PAND X9, X8
+	PXOR X8, X6
+	MOVO X7, X8
+	PANDN X3, X8
+	PXOR X1, X8
+	MOVO X5, X10
+	PANDN X8, X10
+	PXOR X3, X10
+	MOVO X2, X11
+	PANDN X3, X11
+	MOVO X0, X12
+	PXOR X11, X12
+	MOVO X0, X13
+	PANDN X3, X13
+	MOVO X2, X14
+	PXOR X13, X14
+	PANDN X5, X14
+	PXOR X14, X12
+	PANDN X9, X12
+	PXOR X12, X10
+	MOVBQZX 0(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 0(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X12
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X12
+	PANDN X12, X10
+	PXOR X10, X6
+	MOVO X0, X10
+	PANDN X1, X10
+	MOVO X0, X14
+	PAND X3, X14
+	PXOR X10, X14
+	POR X5, X14
+	PXOR X14, X4
+	MOVO X2, X14
+	POR X3, X14
+	MOVOU X6, 0(SP)
+	MOVO X1, X6
+	PANDN X0, X6
+	MOVOU X8, 16(SP)
+	MOVO X6, X8
+	PAND X5, X8
+	PXOR X14, X8
+	PAND X9, X8
+	PXOR X8, X4
+	MOVO X0, X8
+	POR X1, X8
+	MOVOU X2, 32(SP)
+	MOVO X8, X2
+	PANDN X3, X2
+	MOVOU X8, 48(SP)
+	MOVO X7, X8
+	PXOR X2, X8
+	PANDN X5, X8
+	PXOR X14, X8
+	MOVOU X11, 64(SP)
+	MOVO X7, X11
+	PAND X3, X11
+	PXOR X1, X11
+	MOVOU X1, 80(SP)
+	MOVO X7, X1
+	PXOR X13, X1
+	PANDN X5, X1
+	PXOR X1, X11
+	PAND X9, X11
+	PXOR X11, X8
+	PAND X12, X8
+	PXOR X8, X4
+	MOVO X5, X8
+	PANDN X14, X8
+	PXOR X3, X8
+	MOVO X6, X11
+	PANDN X3, X11
+	PXOR X10, X11
+	POR X3, X0
+	PANDN X5, X0
+	PXOR X11, X0
+	PANDN X9, X0
+	PXOR X0, X8
+	MOVO X2, X0
+	PAND X5, X0
+	PXOR X0, X11
+	PXOR X1, X2
+	PAND X9, X2
+	PXOR X2, X11
+	PANDN X12, X11
+	PXOR X11, X8
+	MOVOU 64(SP), X0
+	PXOR X0, X7
+	PAND X3, X6
+	MOVOU 80(SP), X0
+	PXOR X6, X0
+	PAND X5, X0
+	PXOR X7, X0
+	MOVOU 32(SP), X1
+	PAND X3, X1
+	PANDN X9, X1
+	PXOR X1, X0
+	PAND X3, X10
+	MOVOU 48(SP), X1
+	PXOR X1, X10
+	MOVOU 16(SP), X2
+	PANDN X5, X2
+	PXOR X2, X10
+	PXOR X13, X1
+	POR X5, X1
+	PXOR X1, X7
+	PANDN X9, X7
+	PXOR X7, X10
+	PAND X12, X10
+	PXOR X10, X0
+	MOVOU 0(SP), X1
+	MOVOU 256(DI), X15
+	PXOR X15, X1
+	PCMPEQB X15, X15
+	PXOR X15, X1
+	MOVOU X1, 256(DI)
+	MOVOU 512(DI), X15
+	PXOR X15, X4
+	PCMPEQB X15, X15
+	PXOR X15, X4
+	MOVOU X4, 512(DI)
+	MOVOU 704(DI), X15
+	PXOR X15, X8
+	PCMPEQB X15, X15
+	PXOR X15, X8
+	MOVOU X8, 704(DI)
+	MOVOU 960(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 960(DI)
+
+	// S-box 2
+	MOVBQZX 6(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 6(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 10(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 10(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	PXOR X1, X2
+	MOVO X0, X3
+	PANDN X1, X3
+	MOVBQZX 9(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 9(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X4
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X4
+	MOVO X3, X5
+	PAND X4, X5
+	MOVO X2, X6
+	PXOR X5, X6
+	MOVBQZX 8(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 8(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X7
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X7
+	PXOR X7, X6
+	MOVO X0, X8
+	PAND X1, X8
+	MOVO X4, X9
+	PANDN X8, X9
+	MOVO X8, X10
+	PAND X7, X10
+	MOVO X9, X11
+	PXOR X10, X11
+	MOVBQZX 11(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 11(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X12
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X12
+	PANDN X12, X11
+	PXOR X11, X6
+	PANDN X4, X3
+	MOVO X0, X11
+	PANDN X7, X11
+	PXOR X11, X3
+	MOVO X0, X11
+	POR X7, X11
+	PXOR X11, X9
+	PANDN X12, X9
+	PXOR X9, X3
+	MOVBQZX 7(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 7(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X9
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X9
+	PAND X9, X3
+	PXOR X3, X6
+	MOVO X2, X3
+	PXOR X4, X3
+	MOVO X1, X11
+	PAND X4, X11
+	MOVO X11, X13
+	POR X7, X13
+	PANDN X12, X13
+	PXOR X13, X3
+	MOVO X8, X13
+	PANDN X4, X13
+	PXOR X13, X10
+	MOVO X12, X14
+	PANDN X10, X14
+	PXOR X7, X14
+	PANDN X9, X14
+	PXOR X14, X3
+	PXOR X2, X13
+	MOVO X0, X10
+	POR X1, X10
+	MOVO X10, X14
+	POR X4, X14
+	MOVOU X3, 0(SP)
+	MOVO X14, X3
+	PAND X7, X3
+	PXOR X3, X13
+	MOVO X0, X3
+	PANDN X4, X3
+	PAND X7, X3
+	PXOR X8, X3
+	PAND X12, X3
+	PXOR X3, X13
+	MOVO X4, X3
+	PANDN X0, X3
+	PXOR X8, X3
+	MOVOU X6, 16(SP)
+	MOVO X7, X6
+	PANDN X3, X6
+	MOVO X8, X3
+	POR X7, X3
+	PXOR X3, X14
+	PAND X12, X14
+	PXOR X14, X6
+	PANDN X9, X6
+	PXOR X6, X13
+	MOVO X0, X3
+	PXOR X4, X3
+	MOVO X10, X6
+	PANDN X7, X6
+	PXOR X6, X3
+	MOVO X1, X6
+	PANDN X0, X6
+	MOVO X6, X0
+	POR X7, X0
+	PXOR X0, X5
+	PAND X12, X5
+	PXOR X5, X3
+	PXOR X11, X6
+	PAND X7, X1
+	PXOR X1, X6
+	PAND X4, X2
+	PXOR X2, X8
+	PAND X7, X10
+	PXOR X10, X8
+	PANDN X12, X8
+	PXOR X8, X6
+	PAND X9, X6
+	PXOR X6, X3
+	MOVOU 16(SP), X0
+	MOVOU 384(DI), X15
+	PXOR X15, X0
+	PCMPEQB X15, X15
+	PXOR X15, X0
+	MOVOU X0, 384(DI)
+	MOVOU 0(SP), X0
+	MOVOU 864(DI), X15
+	PXOR X15, X0
+	PCMPEQB X15, X15
+	PXOR X15, X0
+	MOVOU X0, 864(DI)
+	MOVOU 32(DI), X15
+	PXOR X15, X13
+	PCMPEQB X15, X15
+	PXOR X15, X13
+	MOVOU X13, 32(DI)
+	MOVOU 544(DI), X15
+	PXOR X15, X3
+	PCMPEQB X15, X15
+	PXOR X15, X3
+	MOVOU X3, 544(DI)
+
+	// S-box 3
+	MOVBQZX 16(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 16(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 13(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 13(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	PXOR X1, X2
+	MOVBQZX 17(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 17(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X3, X4
+	PANDN X0, X4
+	MOVO X4, X5
+	POR X1, X5
+	MOVO X3, X6
+	PXOR X5, X6
+	MOVBQZX 15(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 15(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X7
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X7
+	MOVO X6, X8
+	PAND X7, X8
+	PXOR X8, X2
+	MOVO X3, X8
+	PAND X0, X8
+	PXOR X1, X8
+	MOVO X8, X9
+	POR X7, X9
+	MOVO X5, X10
+	PXOR X9, X10
+	MOVBQZX 14(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 14(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X11
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X11
+	PANDN X11, X10
+	PXOR X10, X2
+	MOVO X3, X10
+	PXOR X1, X10
+	PANDN X7, X6
+	PXOR X10, X6
+	PANDN X11, X9
+	PXOR X9, X6
+	MOVBQZX 12(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 12(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X9
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X9
+	PAND X9, X6
+	PXOR X6, X2
+	MOVO X3, X6
+	POR X1, X6
+	PXOR X4, X6
+	MOVO X7, X12
+	PANDN X6, X12
+	PXOR X12, X5
+	MOVO X3, X12
+	POR X0, X12
+	MOVO X12, X13
+	PANDN X1, X13
+	MOVO X0, X14
+	PXOR X13, X14
+	MOVOU X2, 0(SP)
+	MOVO X1, X2
+	PAND X7, X2
+	PXOR X2, X14
+	PANDN X11, X14
+	PXOR X14, X5
+	PAND X7, X8
+	PXOR X13, X8
+	MOVO X11, X2
+	PANDN X8, X2
+	PANDN X9, X2
+	PXOR X2, X5
+	MOVO X3, X2
+	PXOR X0, X2
+	MOVO X4, X8
+	PANDN X1, X8
+	PXOR X2, X8
+	MOVO X0, X14
+	POR X1, X14
+	MOVOU X5, 16(SP)
+	MOVO X12, X5
+	PXOR X14, X5
+	PANDN X7, X5
+	PXOR X5, X8
+	POR X7, X6
+	PAND X11, X6
+	PXOR X6, X8
+	MOVO X12, X5
+	PAND X1, X5
+	PXOR X3, X5
+	MOVO X0, X6
+	PANDN X3, X6
+	MOVOU X14, 32(SP)
+	MOVO X6, X14
+	PANDN X1, X14
+	PXOR X14, X12
+	PANDN X7, X12
+	PXOR X12, X5
+	MOVO X3, X12
+	PANDN X1, X12
+	PXOR X12, X6
+	PAND X7, X6
+	PXOR X6, X4
+	PAND X11, X4
+	PXOR X4, X5
+	PANDN X9, X5
+	PXOR X5, X8
+	MOVO X0, X4
+	PANDN X7, X4
+	PXOR X4, X10
+	PAND X11, X0
+	PXOR X0, X10
+	MOVO X7, X0
+	PANDN X2, X0
+	PXOR X0, X13
+	PAND X1, X3
+	PANDN X3, X7
+	MOVOU 32(SP), X0
+	PXOR X7, X0
+	PANDN X11, X0
+	PXOR X0, X13
+	PANDN X9, X13
+	PXOR X13, X10
+	MOVOU 0(SP), X0
+	MOVOU 736(DI), X15
+	PXOR X15, X0
+	PCMPEQB X15, X15
+	PXOR X15, X0
+	MOVOU X0, 736(DI)
+	MOVOU 16(SP), X0
+	MOVOU 480(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 480(DI)
+	MOVOU 928(DI), X15
+	PXOR X15, X8
+	PCMPEQB X15, X15
+	PXOR X15, X8
+	MOVOU X8, 928(DI)
+	MOVOU 160(DI), X15
+	PXOR X15, X10
+	MOVOU X10, 160(DI)
+
+	// S-box 4
+	MOVBQZX 22(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 22(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 20(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 20(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X1, X2
+	PANDN X0, X2
+	MOVBQZX 18(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 18(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X2, X4
+	PXOR X3, X4
+	MOVO X0, X5
+	PANDN X1, X5
+	MOVO X5, X6
+	PANDN X3, X6
+	MOVBQZX 21(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 21(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X7
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X7
+	MOVO X6, X8
+	PANDN X7, X8
+	PXOR X8, X4
+	MOVO X3, X8
+	PANDN X2, X8
+	MOVO X1, X9
+	PXOR X8, X9
+	MOVO X1, X10
+	PXOR X0, X10
+	MOVO X10, X11
+	PANDN X3, X11
+	MOVO X0, X12
+	PXOR X11, X12
+	MOVO X12, X13
+	PAND X7, X13
+	PXOR X13, X9
+	MOVBQZX 19(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 19(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X13
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X13
+	PAND X13, X9
+	PXOR X9, X4
+	MOVO X2, X9
+	PAND X3, X9
+	PXOR X10, X9
+	PXOR X0, X6
+	PANDN X7, X6
+	PXOR X9, X6
+	MOVO X10, X14
+	PAND X7, X14
+	PXOR X14, X8
+	PANDN X13, X8
+	PXOR X8, X6
+	MOVBQZX 23(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 23(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X8
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X8
+	MOVOU X14, 0(SP)
+	MOVO X6, X14
+	PANDN X8, X14
+	PXOR X4, X14
+	MOVOU X14, 16(SP)
+	MOVO X8, X14
+	PANDN X6, X14
+	PXOR X14, X4
+	MOVO X2, X6
+	POR X3, X6
+	MOVO X6, X14
+	PAND X7, X14
+	PXOR X14, X9
+	PANDN X3, X2
+	PXOR X11, X1
+	PAND X7, X1
+	PXOR X1, X2
+	PANDN X13, X2
+	PXOR X2, X9
+	PXOR X6, X0
+	PANDN X7, X0
+	PXOR X0, X12
+	PAND X3, X5
+	PXOR X5, X10
+	MOVOU 0(SP), X0
+	PXOR X0, X10
+	PANDN X13, X10
+	PXOR X10, X12
+	MOVO X12, X0
+	PANDN X8, X0
+	PXOR X9, X0
+	PANDN X12, X8
+	PXOR X8, X9
+	MOVOU 16(SP), X1
+	MOVOU 800(DI), X15
+	PXOR X15, X1
+	MOVOU X1, 800(DI)
+	MOVOU 608(DI), X15
+	PXOR X15, X4
+	PCMPEQB X15, X15
+	PXOR X15, X4
+	MOVOU X4, 608(DI)
+	MOVOU 288(DI), X15
+	PXOR X15, X0
+	PCMPEQB X15, X15
+	PXOR X15, X0
+	MOVOU X0, 288(DI)
+	MOVOU 0(DI), X15
+	PXOR X15, X9
+	PCMPEQB X15, X15
+	PXOR X15, X9
+	MOVOU X9, 0(DI)
+
+	// S-box 5
+	MOVBQZX 29(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 29(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 26(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 26(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	POR X1, X2
+	MOVBQZX 27(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 27(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X3, X4
+	PANDN X2, X4
+	MOVO X1, X5
+	PXOR X4, X5
+	MOVO X0, X6
+	PAND X1, X6
+	MOVO X0, X7
+	PANDN X3, X7
+	PXOR X6, X7
+	MOVBQZX 25(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 25(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X8
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X8
+	PANDN X8, X7
+	PXOR X7, X5
+	MOVO X0, X7
+	PANDN X1, X7
+	MOVO X7, X9
+	PANDN X3, X9
+	MOVO X0, X10
+	PXOR X9, X10
+	MOVO X6, X11
+	PXOR X3, X11
+	MOVO X11, X12
+	PAND X8, X12
+	PXOR X12, X10
+	MOVBQZX 28(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 28(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X12
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X12
+	PANDN X12, X10
+	PXOR X10, X5
+	MOVO X0, X10
+	PAND X3, X10
+	MOVO X7, X13
+	PXOR X10, X13
+	MOVO X0, X14
+	PXOR X1, X14
+	MOVOU X1, 0(SP)
+	MOVO X14, X1
+	PANDN X3, X1
+	MOVOU X7, 16(SP)
+	MOVO X6, X7
+	PXOR X1, X7
+	MOVOU X10, 32(SP)
+	MOVO X7, X10
+	PAND X8, X10
+	PXOR X10, X13
+	MOVO X0, X10
+	POR X3, X10
+	MOVOU X9, 48(SP)
+	MOVO X8, X9
+	PANDN X10, X9
+	PXOR X9, X7
+	PANDN X12, X7
+	PXOR X7, X13
+	MOVBQZX 24(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 24(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X7
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X7
+	PAND X7, X13
+	PXOR X13, X5
+	MOVO X6, X9
+	POR X3, X9
+	MOVO X8, X13
+	PANDN X9, X13
+	PXOR X14, X13
+	MOVO X3, X9
+	PANDN X6, X9
+	PANDN X12, X9
+	PXOR X9, X13
+	PAND X8, X4
+	PXOR X1, X0
+	PAND X12, X0
+	PXOR X0, X4
+	PANDN X7, X4
+	PXOR X4, X13
+	MOVO X2, X0
+	PAND X3, X0
+	POR X8, X0
+	PXOR X0, X11
+	MOVOU 48(SP), X0
+	MOVO X14, X1
+	PXOR X0, X1
+	MOVO X2, X4
+	PXOR X3, X4
+	PANDN X8, X4
+	MOVO X1, X9
+	PXOR X4, X9
+	PANDN X12, X9
+	PXOR X9, X11
+	MOVO X8, X9
+	PANDN X1, X9
+	PXOR X6, X9
+	MOVOU X5, 64(SP)
+	MOVOU 32(SP), X5
+	MOVOU X13, 80(SP)
+	MOVO X6, X13
+	PXOR X5, X13
+	PANDN X8, X13
+	PXOR X13, X1
+	PAND X12, X1
+	PXOR X1, X9
+	PANDN X7, X9
+	PXOR X9, X11
+	MOVO X6, X1
+	PAND X3, X1
+	MOVOU 16(SP), X9
+	PXOR X1, X9
+	PAND X8, X10
+	PXOR X10, X9
+	MOVO X2, X1
+	PXOR X0, X1
+	PXOR X5, X14
+	PANDN X8, X14
+	PXOR X14, X1
+	PAND X12, X1
+	PXOR X1, X9
+	PANDN X3, X6
+	PXOR X2, X6
+	MOVOU 0(SP), X1
+	PANDN X3, X1
+	PXOR X1, X2
+	PANDN X8, X2
+	PXOR X2, X6
+	PXOR X4, X0
+	PAND X12, X0
+	PXOR X0, X6
+	PAND X7, X6
+	PXOR X6, X9
+	MOVOU 64(SP), X0
+	MOVOU 224(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 224(DI)
+	MOVOU 80(SP), X0
+	MOVOU 416(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 416(DI)
+	MOVOU 768(DI), X15
+	PXOR X15, X11
+	PCMPEQB X15, X15
+	PXOR X15, X11
+	MOVOU X11, 768(DI)
+	MOVOU 64(DI), X15
+	PXOR X15, X9
+	MOVOU X9, 64(DI)
+
+	// S-box 6
+	MOVBQZX 30(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 30(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 33(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 33(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	PXOR X1, X2
+	MOVBQZX 32(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 32(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X2, X4
+	PAND X3, X4
+	MOVO X3, X5
+	PANDN X2, X5
+	MOVBQZX 34(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 34(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X6
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X6
+	MOVO X5, X7
+	PANDN X6, X7
+	PXOR X7, X4
+	MOVO X0, X7
+	POR X1, X7
+	MOVO X2, X8
+	PANDN X3, X8
+	PXOR X7, X8
+	POR X6, X8
+	MOVBQZX 35(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 35(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X9
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X9
+	PAND X9, X8
+	PXOR X8, X4
+	MOVO X3, X8
+	PANDN X7, X8
+	PXOR X8, X2
+	MOVO X0, X10
+	PAND X1, X10
+	MOVO X0, X11
+	PAND X3, X11
+	MOVO X10, X12
+	PXOR X11, X12
+	MOVO X12, X13
+	PAND X6, X13
+	MOVO X2, X14
+	PXOR X13, X14
+	PAND X9, X14
+	PXOR X3, X14
+	MOVOU X5, 0(SP)
+	MOVBQZX 31(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 31(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X5
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X5
+	PANDN X5, X14
+	PXOR X14, X4
+	MOVO X0, X14
+	PANDN X1, X14
+	MOVOU X4, 16(SP)
+	MOVO X14, X4
+	PANDN X3, X4
+	PXOR X4, X10
+	POR X6, X10
+	PXOR X10, X8
+	PXOR X11, X13
+	PANDN X9, X13
+	PXOR X13, X8
+	MOVO X6, X10
+	PANDN X12, X10
+	PXOR X14, X10
+	MOVO X1, X13
+	PANDN X0, X13
+	PAND X3, X13
+	MOVOU X2, 32(SP)
+	MOVO X1, X2
+	PXOR X11, X2
+	MOVOU X1, 48(SP)
+	MOVO X2, X1
+	PAND X6, X1
+	PXOR X1, X13
+	PAND X9, X13
+	PXOR X13, X10
+	PANDN X5, X10
+	PXOR X10, X8
+	MOVO X0, X1
+	POR X3, X1
+	MOVO X1, X10
+	PAND X6, X10
+	PXOR X10, X2
+	PXOR X14, X11
+	MOVO X6, X10
+	PANDN X11, X10
+	PXOR X7, X10
+	PANDN X9, X10
+	PXOR X10, X2
+	MOVO X14, X10
+	PXOR X3, X10
+	PAND X6, X10
+	PXOR X10, X1
+	PANDN X6, X12
+	PAND X9, X12
+	PXOR X12, X1
+	PAND X5, X1
+	PXOR X1, X2
+	PXOR X4, X0
+	MOVOU 32(SP), X1
+	PANDN X6, X1
+	PXOR X1, X0
+	MOVO X3, X1
+	PANDN X14, X1
+	MOVO X6, X4
+	PANDN X1, X4
+	PXOR X4, X7
+	PAND X9, X7
+	PXOR X7, X0
+	MOVOU 48(SP), X1
+	POR X3, X1
+	PANDN X14, X6
+	MOVOU 0(SP), X3
+	PXOR X6, X3
+	PAND X9, X3
+	PXOR X3, X1
+	PAND X5, X1
+	PXOR X1, X0
+	MOVOU 16(SP), X1
+	MOVOU 96(DI), X15
+	PXOR X15, X1
+	PCMPEQB X15, X15
+	PXOR X15, X1
+	MOVOU X1, 96(DI)
+	MOVOU 896(DI), X15
+	PXOR X15, X8
+	PCMPEQB X15, X15
+	PXOR X15, X8
+	MOVOU X8, 896(DI)
+	MOVOU 320(DI), X15
+	PXOR X15, X2
+	MOVOU X2, 320(DI)
+	MOVOU 576(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 576(DI)
+
+	// S-box 7
+	MOVBQZX 37(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 37(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 39(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 39(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X0, X2
+	PAND X1, X2
+	MOVBQZX 40(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 40(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	MOVO X2, X4
+	PXOR X3, X4
+	MOVBQZX 41(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 41(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X5
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X5
+	PXOR X5, X4
+	MOVO X1, X6
+	PANDN X0, X6
+	MOVO X1, X7
+	PAND X3, X7
+	MOVO X6, X8
+	PXOR X7, X8
+	MOVO X5, X9
+	PANDN X8, X9
+	MOVBQZX 38(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 38(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X10
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X10
+	PANDN X10, X9
+	PXOR X9, X4
+	MOVO X0, X9
+	POR X1, X9
+	MOVO X2, X11
+	PANDN X3, X11
+	PXOR X9, X11
+	MOVO X1, X12
+	PANDN X3, X12
+	MOVO X12, X13
+	PANDN X5, X13
+	PXOR X13, X11
+	MOVO X0, X13
+	PXOR X1, X13
+	MOVO X13, X14
+	PANDN X3, X14
+	PXOR X14, X6
+	MOVOU X12, 0(SP)
+	MOVO X8, X12
+	PANDN X5, X12
+	PXOR X12, X6
+	PAND X10, X6
+	PXOR X6, X11
+	MOVBQZX 36(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 36(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X6
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X6
+	PAND X6, X11
+	PXOR X11, X4
+	MOVO X9, X11
+	PXOR X3, X11
+	MOVO X2, X12
+	PAND X3, X12
+	PXOR X0, X12
+	PAND X5, X12
+	PXOR X12, X11
+	MOVO X7, X12
+	PAND X5, X12
+	PXOR X0, X12
+	PAND X10, X12
+	PXOR X12, X11
+	MOVO X3, X12
+	PANDN X2, X12
+	MOVO X12, X2
+	PANDN X5, X2
+	MOVOU X4, 16(SP)
+	MOVO X13, X4
+	PXOR X2, X4
+	MOVOU X2, 32(SP)
+	MOVO X5, X2
+	PANDN X0, X2
+	PXOR X2, X8
+	PANDN X10, X8
+	PXOR X8, X4
+	PANDN X6, X4
+	PXOR X4, X11
+	MOVO X0, X2
+	PANDN X1, X2
+	MOVO X2, X1
+	PANDN X3, X1
+	MOVO X5, X4
+	PANDN X1, X4
+	PXOR X13, X4
+	MOVO X2, X1
+	PXOR X7, X1
+	MOVO X1, X8
+	PANDN X5, X8
+	PANDN X10, X8
+	PXOR X8, X4
+	MOVO X3, X8
+	POR X5, X8
+	PXOR X8, X12
+	PXOR X14, X9
+	MOVO X5, X8
+	PANDN X9, X8
+	PXOR X8, X1
+	PANDN X10, X1
+	PXOR X1, X12
+	PAND X6, X12
+	PXOR X12, X4
+	MOVOU 0(SP), X1
+	PXOR X1, X0
+	MOVOU 32(SP), X1
+	PXOR X1, X0
+	PXOR X13, X7
+	MOVO X7, X1
+	PANDN X10, X1
+	PXOR X1, X0
+	PAND X3, X13
+	PXOR X13, X2
+	PAND X5, X2
+	PAND X5, X7
+	PAND X10, X7
+	PXOR X7, X2
+	PANDN X6, X2
+	PXOR X2, X0
+	MOVOU 16(SP), X1
+	MOVOU 992(DI), X15
+	PXOR X15, X1
+	MOVOU X1, 992(DI)
+	MOVOU 352(DI), X15
+	PXOR X15, X11
+	PCMPEQB X15, X15
+	PXOR X15, X11
+	MOVOU X11, 352(DI)
+	MOVOU 672(DI), X15
+	PXOR X15, X4
+	MOVOU X4, 672(DI)
+	MOVOU 192(DI), X15
+	PXOR X15, X0
+	MOVOU X0, 192(DI)
+
+	// S-box 8
+	MOVBQZX 44(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 44(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X0
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X0
+	MOVBQZX 45(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 45(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X1
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X1
+	MOVO X1, X2
+	PANDN X0, X2
+	MOVBQZX 46(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 46(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X3
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X3
+	PXOR X3, X2
+	MOVO X0, X4
+	PXOR X3, X4
+	MOVO X4, X5
+	PANDN X1, X5
+	MOVBQZX 47(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 47(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X6
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X6
+	MOVO X5, X7
+	PANDN X6, X7
+	PXOR X7, X2
+	MOVO X3, X7
+	PXOR X5, X7
+	MOVO X0, X8
+	PANDN X1, X8
+	MOVO X3, X9
+	PXOR X8, X9
+	PANDN X6, X9
+	PXOR X9, X7
+	MOVBQZX 43(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 43(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X9
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X9
+	PAND X9, X7
+	PXOR X7, X2
+	MOVO X0, X7
+	PAND X3, X7
+	MOVO X4, X10
+	PAND X1, X10
+	PXOR X7, X10
+	MOVO X1, X11
+	PANDN X4, X11
+	MOVO X11, X12
+	PANDN X6, X12
+	PXOR X10, X12
+	MOVO X0, X13
+	PAND X1, X13
+	MOVO X3, X14
+	PANDN X0, X14
+	PXOR X14, X5
+	PAND X6, X5
+	PXOR X13, X5
+	PAND X9, X5
+	PXOR X5, X12
+	MOVBQZX 42(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 42(R9), BX
+	SHLQ $5, BX
+	MOVOU (SI)(AX*1), X5
+	MOVOU (DX)(BX*1), X15
+	PXOR X15, X5
+	PANDN X5, X12
+	PXOR X12, X2
+	PANDN X3, X0
+	MOVO X0, X12
+	PXOR X1, X12
+	PXOR X6, X12
+	MOVOU X2, 0(SP)
+	MOVO X3, X2
+	PANDN X1, X2
+	PXOR X4, X2
+	MOVOU X11, 16(SP)
+	MOVO X2, X11
+	PANDN X9, X11
+	PXOR X11, X12
+	PXOR X0, X8
+	MOVO X6, X11
+	PANDN X8, X11
+	MOVO X4, X8
+	PXOR X11, X8
+	MOVOU X11, 32(SP)
+	MOVO X6, X11
+	PANDN X13, X11
+	PXOR X2, X11
+	PAND X9, X11
+	PXOR X11, X8
+	PAND X5, X8
+	PXOR X8, X12
+	MOVO X1, X8
+	PANDN X3, X8
+	PXOR X14, X8
+	MOVO X4, X11
+	POR X1, X11
+	PANDN X6, X11
+	PANDN X9, X11
+	PXOR X11, X8
+	MOVO X3, X11
+	POR X1, X11
+	MOVO X6, X13
+	PANDN X11, X13
+	PXOR X7, X13
+	PAND X1, X3
+	PXOR X3, X7
+	PAND X6, X7
+	PXOR X0, X7
+	PAND X9, X7
+	PXOR X7, X13
+	PANDN X5, X13
+	PXOR X13, X8
+	PANDN X1, X14
+	PXOR X14, X0
+	PAND X6, X0
+	PXOR X0, X2
+	PAND X6, X4
+	PXOR X4, X3
+	PANDN X9, X3
+	PXOR X3, X2
+	POR X6, X10
+	MOVOU 16(SP), X0
+	PXOR X0, X10
+	MOVOU 32(SP), X1
+	PXOR X1, X0
+	PAND X9, X0
+	PXOR X0, X10
+	PAND X5, X10
+	PXOR X10, X2
+	MOVOU 0(SP), X0
+	MOVOU 128(DI), X15
+	PXOR X15, X0
+	PCMPEQB X15, X15
+	PXOR X15, X0
+	MOVOU X0, 128(DI)
+	MOVOU 832(DI), X15
+	PXOR X15, X12
+	PCMPEQB X15, X15
+	PXOR X15, X12
+	MOVOU X12, 832(DI)
+	MOVOU 448(DI), X15
+	PXOR X15, X8
+	MOVOU X8, 448(DI)
+	MOVOU 640(DI), X15
+	PXOR X15, X2
+	PCMPEQB X15, X15
+	PXOR X15, X2
+	MOVOU X2, 640(DI)
+
+	ADDQ $16, DI
+	ADDQ $16, SI
+	ADDQ $16, DX
+	DECQ CX
+	JNZ loop
+
+done:
+	RET
+
+// func bsRoundAVX2(l, r *bsHalf, k *bsKey, e, kb *[48]uint8, words int)
+TEXT ·bsRoundAVX2(SB), NOSPLIT, $192-48
+	MOVQ l+0(FP), DI
+	MOVQ r+8(FP), SI
+	MOVQ k+16(FP), DX
+	MOVQ e+24(FP), R8
+	MOVQ kb+32(FP), R9
+
+	// S-box 1
+	MOVBQZX 5(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 5(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 4(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 4(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPXOR Y1, Y0, Y2
+	MOVBQZX 1(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 1(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPXOR Y3, Y2, Y4
+	MOVBQZX 2(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 2(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y5
+	VPXOR (DX)(BX*1), Y5, Y5
+	VPANDN Y5, Y3, Y6
+	VPXOR Y6, Y4, Y6
+	VPAND Y1, Y0, Y7
+	VPANDN Y5, Y4, Y8
+	VPXOR Y8, Y7, Y8
+	MOVBQZX 3(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 3(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y9
+	VPXOR (DX)(BX*1), Y9, Y9
+	VPAND Y9, Y8, Y8
+	VPXOR Y8, Y6, Y6
+	VPANDN Y3, Y7, Y8
+	VPXOR Y8, Y1, Y8
+	VPANDN Y8, Y5, Y10
+	VPXOR Y10, Y3, Y10
+	VPANDN Y3, Y2, Y11
+	VPXOR Y11, Y0, Y12
+	VPANDN Y3, Y0, Y13
+	VPXOR Y13, Y2, Y14
+	VPANDN Y5, Y14, Y14
+	VPXOR Y14, Y12, Y12
+	VPANDN Y9, Y12, Y12
+	VPXOR Y12, Y10, Y10
+	MOVBQZX 0(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 0(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y12
+	VPXOR (DX)(BX*1), Y12, Y12
+	VPANDN Y12, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPANDN Y1, Y0, Y10
+	VPAND Y3, Y0, Y14
+	VPXOR Y14, Y10, Y14
+	VPOR Y5, Y14, Y14
+	VPXOR Y14, Y4, Y4
+	VPOR Y3, Y2, Y14
+	VMOVDQU Y6, 0(SP)
+	VPANDN Y0, Y1, Y6
+	VMOVDQU Y8, 32(SP)
+	VPAND Y5, Y6, Y8
+	VPXOR Y8, Y14, Y8
+	VPAND Y9, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPOR Y1, Y0, Y8
+	VMOVDQU Y2, 64(SP)
+	VPANDN Y3, Y8, Y2
+	VMOVDQU Y8, 96(SP)
+	VPXOR Y2, Y7, Y8
+	VPANDN Y5, Y8, Y8
+	VPXOR Y8, Y14, Y8
+	VMOVDQU Y11, 128(SP)
+	VPAND Y3, Y7, Y11
+	VPXOR Y11, Y1, Y11
+	VMOVDQU Y1, 160(SP)
+	VPXOR Y13, Y7, Y1
+	VPANDN Y5, Y1, Y1
+	VPXOR Y1, Y11, Y11
+	VPAND Y9, Y11, Y11
+	VPXOR Y11, Y8, Y8
+	VPAND Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPANDN Y14, Y5, Y14
+	VPXOR Y14, Y3, Y14
+	VPANDN Y3, Y6, Y8
+	VPXOR Y8, Y10, Y8
+	VPOR Y3, Y0, Y0
+	VPANDN Y5, Y0, Y0
+	VPXOR Y0, Y8, Y0
+	VPANDN Y9, Y0, Y0
+	VPXOR Y0, Y14, Y14
+	VPAND Y5, Y2, Y0
+	VPXOR Y0, Y8, Y8
+	VPXOR Y1, Y2, Y2
+	VPAND Y9, Y2, Y2
+	VPXOR Y2, Y8, Y8
+	VPANDN Y12, Y8, Y8
+	VPXOR Y8, Y14, Y14
+	VMOVDQU 128(SP), Y0
+	VPXOR Y0, Y7, Y7
+	VPAND Y3, Y6, Y6
+	VMOVDQU 160(SP), Y0
+	VPXOR Y6, Y0, Y0
+	VPAND Y5, Y0, Y0
+	VPXOR Y0, Y7, Y0
+	VMOVDQU 64(SP), Y1
+	VPAND Y3, Y1, Y1
+	VPANDN Y9, Y1, Y1
+	VPXOR Y1, Y0, Y0
+	VPAND Y3, Y10, Y10
+	VMOVDQU 96(SP), Y1
+	VPXOR Y10, Y1, Y10
+	VMOVDQU 32(SP), Y2
+	VPANDN Y5, Y2, Y2
+	VPXOR Y2, Y10, Y10
+	VPXOR Y13, Y1, Y1
+	VPOR Y5, Y1, Y1
+	VPXOR Y1, Y7, Y7
+	VPANDN Y9, Y7, Y9
+	VPXOR Y9, Y10, Y10
+	VPAND Y12, Y10, Y10
+	VPXOR Y10, Y0, Y0
+	VMOVDQU 0(SP), Y1
+	VPXOR 256(DI), Y1, Y1
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y1, Y1
+	VMOVDQU Y1, 256(DI)
+	VPXOR 512(DI), Y4, Y4
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y4, Y4
+	VMOVDQU Y4, 512(DI)
+	VPXOR 704(DI), Y14, Y14
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y14, Y14
+	VMOVDQU Y14, 704(DI)
+	VPXOR 960(DI), Y0, Y0
+	VMOVDQU Y0, 960(DI)
+
+	// S-box 2
+	MOVBQZX 6(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 6(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 10(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 10(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPXOR Y1, Y0, Y2
+	VPANDN Y1, Y0, Y3
+	MOVBQZX 9(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 9(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y4
+	VPXOR (DX)(BX*1), Y4, Y4
+	VPAND Y4, Y3, Y5
+	VPXOR Y5, Y2, Y6
+	MOVBQZX 8(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 8(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y7
+	VPXOR (DX)(BX*1), Y7, Y7
+	VPXOR Y7, Y6, Y6
+	VPAND Y1, Y0, Y8
+	VPANDN Y8, Y4, Y9
+	VPAND Y7, Y8, Y10
+	VPXOR Y10, Y9, Y11
+	MOVBQZX 11(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 11(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y12
+	VPXOR (DX)(BX*1), Y12, Y12
+	VPANDN Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPANDN Y4, Y3, Y3
+	VPANDN Y7, Y0, Y11
+	VPXOR Y11, Y3, Y3
+	VPOR Y7, Y0, Y11
+	VPXOR Y11, Y9, Y9
+	VPANDN Y12, Y9, Y9
+	VPXOR Y9, Y3, Y3
+	MOVBQZX 7(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 7(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y9
+	VPXOR (DX)(BX*1), Y9, Y9
+	VPAND Y9, Y3, Y3
+	VPXOR Y3, Y6, Y6
+	VPXOR Y4, Y2, Y3
+	VPAND Y4, Y1, Y11
+	VPOR Y7, Y11, Y13
+	VPANDN Y12, Y13, Y13
+	VPXOR Y13, Y3, Y3
+	VPANDN Y4, Y8, Y13
+	VPXOR Y10, Y13, Y10
+	VPANDN Y10, Y12, Y10
+	VPXOR Y10, Y7, Y10
+	VPANDN Y9, Y10, Y10
+	VPXOR Y10, Y3, Y3
+	VPXOR Y13, Y2, Y13
+	VPOR Y1, Y0, Y10
+	VPOR Y4, Y10, Y14
+	VMOVDQU Y3, 0(SP)
+	VPAND Y7, Y14, Y3
+	VPXOR Y3, Y13, Y13
+	VPANDN Y4, Y0, Y3
+	VPAND Y7, Y3, Y3
+	VPXOR Y3, Y8, Y3
+	VPAND Y12, Y3, Y3
+	VPXOR Y3, Y13, Y13
+	VPANDN Y0, Y4, Y3
+	VPXOR Y3, Y8, Y3
+	VPANDN Y3, Y7, Y3
+	VMOVDQU Y6, 32(SP)
+	VPOR Y7, Y8, Y6
+	VPXOR Y6, Y14, Y14
+	VPAND Y12, Y14, Y14
+	VPXOR Y14, Y3, Y3
+	VPANDN Y9, Y3, Y3
+	VPXOR Y3, Y13, Y13
+	VPXOR Y4, Y0, Y3
+	VPANDN Y7, Y10, Y6
+	VPXOR Y6, Y3, Y3
+	VPANDN Y0, Y1, Y0
+	VPOR Y7, Y0, Y6
+	VPXOR Y6, Y5, Y5
+	VPAND Y12, Y5, Y5
+	VPXOR Y5, Y3, Y3
+	VPXOR Y11, Y0, Y0
+	VPAND Y7, Y1, Y1
+	VPXOR Y1, Y0, Y0
+	VPAND Y4, Y2, Y2
+	VPXOR Y2, Y8, Y8
+	VPAND Y7, Y10, Y10
+	VPXOR Y10, Y8, Y8
+	VPANDN Y12, Y8, Y12
+	VPXOR Y12, Y0, Y0
+	VPAND Y9, Y0, Y0
+	VPXOR Y0, Y3, Y3
+	VMOVDQU 32(SP), Y0
+	VPXOR 384(DI), Y0, Y0
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y0, Y0
+	VMOVDQU Y0, 384(DI)
+	VMOVDQU 0(SP), Y0
+	VPXOR 864(DI), Y0, Y0
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y0, Y0
+	VMOVDQU Y0, 864(DI)
+	VPXOR 32(DI), Y13, Y13
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y13, Y13
+	VMOVDQU Y13, 32(DI)
+	VPXOR 544(DI), Y3, Y3
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y3, Y3
+	VMOVDQU Y3, 544(DI)
+
+	// S-box 3
+	MOVBQZX 16(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 16(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 13(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 13(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPXOR Y1, Y0, Y2
+	MOVBQZX 17(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 17(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPANDN Y0, Y3, Y4
+	VPOR Y1, Y4, Y5
+	VPXOR Y5, Y3, Y6
+	MOVBQZX 15(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 15(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y7
+	VPXOR (DX)(BX*1), Y7, Y7
+	VPAND Y7, Y6, Y8
+	VPXOR Y8, Y2, Y2
+	VPAND Y0, Y3, Y8
+	VPXOR Y1, Y8, Y8
+	VPOR Y7, Y8, Y9
+	VPXOR Y9, Y5, Y10
+	MOVBQZX 14(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 14(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y11
+	VPXOR (DX)(BX*1), Y11, Y11
+	VPANDN Y11, Y10, Y10
+	VPXOR Y10, Y2, Y2
+	VPXOR Y1, Y3, Y10
+	VPANDN Y7, Y6, Y6
+	VPXOR Y6, Y10, Y6
+	VPANDN Y11, Y9, Y9
+	VPXOR Y9, Y6, Y6
+	MOVBQZX 12(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 12(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y9
+	VPXOR (DX)(BX*1), Y9, Y9
+	VPAND Y9, Y6, Y6
+	VPXOR Y6, Y2, Y2
+	VPOR Y1, Y3, Y6
+	VPXOR Y6, Y4, Y6
+	VPANDN Y6, Y7, Y12
+	VPXOR Y12, Y5, Y5
+	VPOR Y0, Y3, Y12
+	VPANDN Y1, Y12, Y13
+	VPXOR Y13, Y0, Y14
+	VMOVDQU Y2, 0(SP)
+	VPAND Y7, Y1, Y2
+	VPXOR Y2, Y14, Y14
+	VPANDN Y11, Y14, Y14
+	VPXOR Y14, Y5, Y5
+	VPAND Y7, Y8, Y8
+	VPXOR Y8, Y13, Y8
+	VPANDN Y8, Y11, Y8
+	VPANDN Y9, Y8, Y8
+	VPXOR Y8, Y5, Y5
+	VPXOR Y0, Y3, Y2
+	VPANDN Y1, Y4, Y8
+	VPXOR Y8, Y2, Y8
+	VPOR Y1, Y0, Y14
+	VMOVDQU Y5, 32(SP)
+	VPXOR Y14, Y12, Y5
+	VPANDN Y7, Y5, Y5
+	VPXOR Y5, Y8, Y8
+	VPOR Y7, Y6, Y6
+	VPAND Y11, Y6, Y6
+	VPXOR Y6, Y8, Y8
+	VPAND Y1, Y12, Y5
+	VPXOR Y5, Y3, Y5
+	VPANDN Y3, Y0, Y6
+	VMOVDQU Y14, 64(SP)
+	VPANDN Y1, Y6, Y14
+	VPXOR Y14, Y12, Y12
+	VPANDN Y7, Y12, Y12
+	VPXOR Y12, Y5, Y5
+	VPANDN Y1, Y3, Y12
+	VPXOR Y12, Y6, Y6
+	VPAND Y7, Y6, Y6
+	VPXOR Y6, Y4, Y4
+	VPAND Y11, Y4, Y4
+	VPXOR Y4, Y5, Y5
+	VPANDN Y9, Y5, Y5
+	VPXOR Y5, Y8, Y8
+	VPANDN Y7, Y0, Y4
+	VPXOR Y4, Y10, Y10
+	VPAND Y11, Y0, Y0
+	VPXOR Y0, Y10, Y10
+	VPANDN Y2, Y7, Y2
+	VPXOR Y2, Y13, Y13
+	VPAND Y1, Y3, Y3
+	VPANDN Y3, Y7, Y3
+	VMOVDQU 64(SP), Y0
+	VPXOR Y3, Y0, Y0
+	VPANDN Y11, Y0, Y11
+	VPXOR Y11, Y13, Y13
+	VPANDN Y9, Y13, Y9
+	VPXOR Y9, Y10, Y10
+	VMOVDQU 0(SP), Y0
+	VPXOR 736(DI), Y0, Y0
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y0, Y0
+	VMOVDQU Y0, 736(DI)
+	VMOVDQU 32(SP), Y0
+	VPXOR 480(DI), Y0, Y0
+	VMOVDQU Y0, 480(DI)
+	VPXOR 928(DI), Y8, Y8
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y8, Y8
+	VMOVDQU Y8, 928(DI)
+	VPXOR 160(DI), Y10, Y10
+	VMOVDQU Y10, 160(DI)
+
+	// S-box 4
+	MOVBQZX 22(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 22(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 20(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 20(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPANDN Y0, Y1, Y2
+	MOVBQZX 18(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 18(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPXOR Y3, Y2, Y4
+	VPANDN Y1, Y0, Y5
+	VPANDN Y3, Y5, Y6
+	MOVBQZX 21(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 21(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y7
+	VPXOR (DX)(BX*1), Y7, Y7
+	VPANDN Y7, Y6, Y8
+	VPXOR Y8, Y4, Y4
+	VPANDN Y2, Y3, Y8
+	VPXOR Y8, Y1, Y9
+	VPXOR Y0, Y1, Y10
+	VPANDN Y3, Y10, Y11
+	VPXOR Y11, Y0, Y12
+	VPAND Y7, Y12, Y13
+	VPXOR Y13, Y9, Y9
+	MOVBQZX 19(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 19(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y13
+	VPXOR (DX)(BX*1), Y13, Y13
+	VPAND Y13, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPAND Y3, Y2, Y9
+	VPXOR Y9, Y10, Y9
+	VPXOR Y6, Y0, Y6
+	VPANDN Y7, Y6, Y6
+	VPXOR Y6, Y9, Y6
+	VPAND Y7, Y10, Y14
+	VPXOR Y14, Y8, Y8
+	VPANDN Y13, Y8, Y8
+	VPXOR Y8, Y6, Y6
+	MOVBQZX 23(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 23(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y8
+	VPXOR (DX)(BX*1), Y8, Y8
+	VMOVDQU Y14, 0(SP)
+	VPANDN Y8, Y6, Y14
+	VPXOR Y14, Y4, Y14
+	VPANDN Y6, Y8, Y6
+	VPXOR Y6, Y4, Y4
+	VPOR Y3, Y2, Y6
+	VMOVDQU Y4, 32(SP)
+	VPAND Y7, Y6, Y4
+	VPXOR Y4, Y9, Y9
+	VPANDN Y3, Y2, Y2
+	VPXOR Y11, Y1, Y1
+	VPAND Y7, Y1, Y1
+	VPXOR Y1, Y2, Y2
+	VPANDN Y13, Y2, Y2
+	VPXOR Y2, Y9, Y9
+	VPXOR Y6, Y0, Y0
+	VPANDN Y7, Y0, Y7
+	VPXOR Y7, Y12, Y12
+	VPAND Y3, Y5, Y5
+	VPXOR Y5, Y10, Y10
+	VMOVDQU 0(SP), Y0
+	VPXOR Y0, Y10, Y10
+	VPANDN Y13, Y10, Y13
+	VPXOR Y13, Y12, Y12
+	VPANDN Y8, Y12, Y0
+	VPXOR Y0, Y9, Y0
+	VPANDN Y12, Y8, Y12
+	VPXOR Y12, Y9, Y9
+	VPXOR 800(DI), Y14, Y14
+	VMOVDQU Y14, 800(DI)
+	VMOVDQU 32(SP), Y1
+	VPXOR 608(DI), Y1, Y1
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y1, Y1
+	VMOVDQU Y1, 608(DI)
+	VPXOR 288(DI), Y0, Y0
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y0, Y0
+	VMOVDQU Y0, 288(DI)
+	VPXOR 0(DI), Y9, Y9
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y9, Y9
+	VMOVDQU Y9, 0(DI)
+
+	// S-box 5
+	MOVBQZX 29(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 29(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 26(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 26(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPOR Y1, Y0, Y2
+	MOVBQZX 27(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 27(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPANDN Y2, Y3, Y4
+	VPXOR Y4, Y1, Y5
+	VPAND Y1, Y0, Y6
+	VPANDN Y3, Y0, Y7
+	VPXOR Y7, Y6, Y7
+	MOVBQZX 25(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 25(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y8
+	VPXOR (DX)(BX*1), Y8, Y8
+	VPANDN Y8, Y7, Y7
+	VPXOR Y7, Y5, Y5
+	VPANDN Y1, Y0, Y7
+	VPANDN Y3, Y7, Y9
+	VPXOR Y9, Y0, Y10
+	VPXOR Y3, Y6, Y11
+	VPAND Y8, Y11, Y12
+	VPXOR Y12, Y10, Y10
+	MOVBQZX 28(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 28(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y12
+	VPXOR (DX)(BX*1), Y12, Y12
+	VPANDN Y12, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPAND Y3, Y0, Y10
+	VPXOR Y10, Y7, Y13
+	VPXOR Y1, Y0, Y14
+	VMOVDQU Y1, 0(SP)
+	VPANDN Y3, Y14, Y1
+	VMOVDQU Y7, 32(SP)
+	VPXOR Y1, Y6, Y7
+	VMOVDQU Y10, 64(SP)
+	VPAND Y8, Y7, Y10
+	VPXOR Y10, Y13, Y13
+	VPOR Y3, Y0, Y10
+	VMOVDQU Y9, 96(SP)
+	VPANDN Y10, Y8, Y9
+	VPXOR Y9, Y7, Y7
+	VPANDN Y12, Y7, Y7
+	VPXOR Y7, Y13, Y13
+	MOVBQZX 24(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 24(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y7
+	VPXOR (DX)(BX*1), Y7, Y7
+	VPAND Y7, Y13, Y13
+	VPXOR Y13, Y5, Y5
+	VPOR Y3, Y6, Y9
+	VPANDN Y9, Y8, Y9
+	VPXOR Y9, Y14, Y9
+	VPANDN Y6, Y3, Y13
+	VPANDN Y12, Y13, Y13
+	VPXOR Y13, Y9, Y9
+	VPAND Y8, Y4, Y4
+	VPXOR Y1, Y0, Y0
+	VPAND Y12, Y0, Y0
+	VPXOR Y0, Y4, Y4
+	VPANDN Y7, Y4, Y4
+	VPXOR Y4, Y9, Y9
+	VPAND Y3, Y2, Y0
+	VPOR Y8, Y0, Y0
+	VPXOR Y0, Y11, Y11
+	VMOVDQU 96(SP), Y0
+	VPXOR Y0, Y14, Y1
+	VPXOR Y3, Y2, Y4
+	VPANDN Y8, Y4, Y4
+	VPXOR Y4, Y1, Y13
+	VPANDN Y12, Y13, Y13
+	VPXOR Y13, Y11, Y11
+	VPANDN Y1, Y8, Y13
+	VPXOR Y13, Y6, Y13
+	VMOVDQU Y5, 128(SP)
+	VMOVDQU 64(SP), Y5
+	VMOVDQU Y9, 160(SP)
+	VPXOR Y5, Y6, Y9
+	VPANDN Y8, Y9, Y9
+	VPXOR Y9, Y1, Y1
+	VPAND Y12, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPANDN Y7, Y13, Y13
+	VPXOR Y13, Y11, Y11
+	VPAND Y3, Y6, Y1
+	VMOVDQU 32(SP), Y9
+	VPXOR Y1, Y9, Y9
+	VPAND Y8, Y10, Y10
+	VPXOR Y10, Y9, Y9
+	VPXOR Y0, Y2, Y1
+	VPXOR Y5, Y14, Y14
+	VPANDN Y8, Y14, Y14
+	VPXOR Y14, Y1, Y1
+	VPAND Y12, Y1, Y1
+	VPXOR Y1, Y9, Y9
+	VPANDN Y3, Y6, Y6
+	VPXOR Y6, Y2, Y6
+	VMOVDQU 0(SP), Y1
+	VPANDN Y3, Y1, Y3
+	VPXOR Y3, Y2, Y2
+	VPANDN Y8, Y2, Y8
+	VPXOR Y8, Y6, Y6
+	VPXOR Y4, Y0, Y0
+	VPAND Y12, Y0, Y0
+	VPXOR Y0, Y6, Y6
+	VPAND Y7, Y6, Y6
+	VPXOR Y6, Y9, Y9
+	VMOVDQU 128(SP), Y0
+	VPXOR 224(DI), Y0, Y0
+	VMOVDQU Y0, 224(DI)
+	VMOVDQU 160(SP), Y0
+	VPXOR 416(DI), Y0, Y0
+	VMOVDQU Y0, 416(DI)
+	VPXOR 768(DI), Y11, Y11
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y11, Y11
+	VMOVDQU Y11, 768(DI)
+	VPXOR 64(DI), Y9, Y9
+	VMOVDQU Y9, 64(DI)
+
+	// S-box 6
+	MOVBQZX 30(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 30(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 33(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 33(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPXOR Y1, Y0, Y2
+	MOVBQZX 32(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 32(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPAND Y3, Y2, Y4
+	VPANDN Y2, Y3, Y5
+	MOVBQZX 34(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 34(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y6
+	VPXOR (DX)(BX*1), Y6, Y6
+	VPANDN Y6, Y5, Y7
+	VPXOR Y7, Y4, Y4
+	VPOR Y1, Y0, Y7
+	VPANDN Y3, Y2, Y8
+	VPXOR Y8, Y7, Y8
+	VPOR Y6, Y8, Y8
+	MOVBQZX 35(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 35(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y9
+	VPXOR (DX)(BX*1), Y9, Y9
+	VPAND Y9, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPANDN Y7, Y3, Y8
+	VPXOR Y8, Y2, Y2
+	VPAND Y1, Y0, Y10
+	VPAND Y3, Y0, Y11
+	VPXOR Y11, Y10, Y12
+	VPAND Y6, Y12, Y13
+	VPXOR Y13, Y2, Y14
+	VPAND Y9, Y14, Y14
+	VPXOR Y14, Y3, Y14
+	VMOVDQU Y5, 0(SP)
+	MOVBQZX 31(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 31(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y5
+	VPXOR (DX)(BX*1), Y5, Y5
+	VPANDN Y5, Y14, Y14
+	VPXOR Y14, Y4, Y4
+	VPANDN Y1, Y0, Y14
+	VMOVDQU Y4, 32(SP)
+	VPANDN Y3, Y14, Y4
+	VPXOR Y4, Y10, Y10
+	VPOR Y6, Y10, Y10
+	VPXOR Y10, Y8, Y8
+	VPXOR Y13, Y11, Y13
+	VPANDN Y9, Y13, Y13
+	VPXOR Y13, Y8, Y8
+	VPANDN Y12, Y6, Y10
+	VPXOR Y10, Y14, Y10
+	VPANDN Y0, Y1, Y13
+	VPAND Y3, Y13, Y13
+	VMOVDQU Y2, 64(SP)
+	VPXOR Y11, Y1, Y2
+	VMOVDQU Y1, 96(SP)
+	VPAND Y6, Y2, Y1
+	VPXOR Y1, Y13, Y13
+	VPAND Y9, Y13, Y13
+	VPXOR Y13, Y10, Y10
+	VPANDN Y5, Y10, Y10
+	VPXOR Y10, Y8, Y8
+	VPOR Y3, Y0, Y1
+	VPAND Y6, Y1, Y10
+	VPXOR Y10, Y2, Y2
+	VPXOR Y11, Y14, Y11
+	VPANDN Y11, Y6, Y11
+	VPXOR Y11, Y7, Y11
+	VPANDN Y9, Y11, Y11
+	VPXOR Y11, Y2, Y2
+	VPXOR Y3, Y14, Y10
+	VPAND Y6, Y10, Y10
+	VPXOR Y10, Y1, Y1
+	VPANDN Y6, Y12, Y12
+	VPAND Y9, Y12, Y12
+	VPXOR Y12, Y1, Y1
+	VPAND Y5, Y1, Y1
+	VPXOR Y1, Y2, Y2
+	VPXOR Y4, Y0, Y0
+	VMOVDQU 64(SP), Y1
+	VPANDN Y6, Y1, Y1
+	VPXOR Y1, Y0, Y0
+	VPANDN Y14, Y3, Y1
+	VPANDN Y1, Y6, Y1
+	VPXOR Y1, Y7, Y7
+	VPAND Y9, Y7, Y7
+	VPXOR Y7, Y0, Y0
+	VMOVDQU 96(SP), Y1
+	VPOR Y3, Y1, Y1
+	VPANDN Y14, Y6, Y14
+	VMOVDQU 0(SP), Y3
+	VPXOR Y14, Y3, Y3
+	VPAND Y9, Y3, Y3
+	VPXOR Y3, Y1, Y1
+	VPAND Y5, Y1, Y1
+	VPXOR Y1, Y0, Y0
+	VMOVDQU 32(SP), Y1
+	VPXOR 96(DI), Y1, Y1
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y1, Y1
+	VMOVDQU Y1, 96(DI)
+	VPXOR 896(DI), Y8, Y8
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y8, Y8
+	VMOVDQU Y8, 896(DI)
+	VPXOR 320(DI), Y2, Y2
+	VMOVDQU Y2, 320(DI)
+	VPXOR 576(DI), Y0, Y0
+	VMOVDQU Y0, 576(DI)
+
+	// S-box 7
+	MOVBQZX 37(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 37(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 39(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 39(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPAND Y1, Y0, Y2
+	MOVBQZX 40(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 40(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPXOR Y3, Y2, Y4
+	MOVBQZX 41(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 41(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y5
+	VPXOR (DX)(BX*1), Y5, Y5
+	VPXOR Y5, Y4, Y4
+	VPANDN Y0, Y1, Y6
+	VPAND Y3, Y1, Y7
+	VPXOR Y7, Y6, Y8
+	VPANDN Y8, Y5, Y9
+	MOVBQZX 38(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 38(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y10
+	VPXOR (DX)(BX*1), Y10, Y10
+	VPANDN Y10, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPOR Y1, Y0, Y9
+	VPANDN Y3, Y2, Y11
+	VPXOR Y11, Y9, Y11
+	VPANDN Y3, Y1, Y12
+	VPANDN Y5, Y12, Y13
+	VPXOR Y13, Y11, Y11
+	VPXOR Y1, Y0, Y13
+	VPANDN Y3, Y13, Y14
+	VPXOR Y14, Y6, Y6
+	VMOVDQU Y12, 0(SP)
+	VPANDN Y5, Y8, Y12
+	VPXOR Y12, Y6, Y6
+	VPAND Y10, Y6, Y6
+	VPXOR Y6, Y11, Y11
+	MOVBQZX 36(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 36(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y6
+	VPXOR (DX)(BX*1), Y6, Y6
+	VPAND Y6, Y11, Y11
+	VPXOR Y11, Y4, Y4
+	VPXOR Y3, Y9, Y11
+	VPAND Y3, Y2, Y12
+	VPXOR Y12, Y0, Y12
+	VPAND Y5, Y12, Y12
+	VPXOR Y12, Y11, Y11
+	VPAND Y5, Y7, Y12
+	VPXOR Y12, Y0, Y12
+	VPAND Y10, Y12, Y12
+	VPXOR Y12, Y11, Y11
+	VPANDN Y2, Y3, Y2
+	VPANDN Y5, Y2, Y12
+	VMOVDQU Y4, 32(SP)
+	VPXOR Y12, Y13, Y4
+	VMOVDQU Y12, 64(SP)
+	VPANDN Y0, Y5, Y12
+	VPXOR Y12, Y8, Y8
+	VPANDN Y10, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPANDN Y6, Y4, Y4
+	VPXOR Y4, Y11, Y11
+	VPANDN Y1, Y0, Y1
+	VPANDN Y3, Y1, Y4
+	VPANDN Y4, Y5, Y4
+	VPXOR Y4, Y13, Y4
+	VPXOR Y7, Y1, Y8
+	VPANDN Y5, Y8, Y12
+	VPANDN Y10, Y12, Y12
+	VPXOR Y12, Y4, Y4
+	VPOR Y5, Y3, Y12
+	VPXOR Y12, Y2, Y2
+	VPXOR Y14, Y9, Y9
+	VPANDN Y9, Y5, Y9
+	VPXOR Y9, Y8, Y8
+	VPANDN Y10, Y8, Y8
+	VPXOR Y8, Y2, Y2
+	VPAND Y6, Y2, Y2
+	VPXOR Y2, Y4, Y4
+	VMOVDQU 0(SP), Y2
+	VPXOR Y2, Y0, Y0
+	VMOVDQU 64(SP), Y2
+	VPXOR Y2, Y0, Y0
+	VPXOR Y7, Y13, Y7
+	VPANDN Y10, Y7, Y2
+	VPXOR Y2, Y0, Y0
+	VPAND Y3, Y13, Y13
+	VPXOR Y13, Y1, Y1
+	VPAND Y5, Y1, Y1
+	VPAND Y5, Y7, Y7
+	VPAND Y10, Y7, Y7
+	VPXOR Y7, Y1, Y1
+	VPANDN Y6, Y1, Y6
+	VPXOR Y6, Y0, Y0
+	VMOVDQU 32(SP), Y1
+	VPXOR 992(DI), Y1, Y1
+	VMOVDQU Y1, 992(DI)
+	VPXOR 352(DI), Y11, Y11
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y11, Y11
+	VMOVDQU Y11, 352(DI)
+	VPXOR 672(DI), Y4, Y4
+	VMOVDQU Y4, 672(DI)
+	VPXOR 192(DI), Y0, Y0
+	VMOVDQU Y0, 192(DI)
+
+	// S-box 8
+	MOVBQZX 44(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 44(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y0
+	VPXOR (DX)(BX*1), Y0, Y0
+	MOVBQZX 45(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 45(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y1
+	VPXOR (DX)(BX*1), Y1, Y1
+	VPANDN Y0, Y1, Y2
+	MOVBQZX 46(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 46(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y3
+	VPXOR (DX)(BX*1), Y3, Y3
+	VPXOR Y2, Y3, Y2
+	VPXOR Y3, Y0, Y4
+	VPANDN Y1, Y4, Y5
+	MOVBQZX 47(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 47(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y6
+	VPXOR (DX)(BX*1), Y6, Y6
+	VPANDN Y6, Y5, Y7
+	VPXOR Y7, Y2, Y2
+	VPXOR Y5, Y3, Y7
+	VPANDN Y1, Y0, Y8
+	VPXOR Y8, Y3, Y9
+	VPANDN Y6, Y9, Y9
+	VPXOR Y9, Y7, Y7
+	MOVBQZX 43(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 43(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y9
+	VPXOR (DX)(BX*1), Y9, Y9
+	VPAND Y9, Y7, Y7
+	VPXOR Y7, Y2, Y2
+	VPAND Y3, Y0, Y7
+	VPAND Y1, Y4, Y10
+	VPXOR Y10, Y7, Y10
+	VPANDN Y4, Y1, Y11
+	VPANDN Y6, Y11, Y12
+	VPXOR Y12, Y10, Y12
+	VPAND Y1, Y0, Y13
+	VPANDN Y0, Y3, Y14
+	VPXOR Y5, Y14, Y5
+	VPAND Y6, Y5, Y5
+	VPXOR Y5, Y13, Y5
+	VPAND Y9, Y5, Y5
+	VPXOR Y5, Y12, Y12
+	MOVBQZX 42(R8), AX
+	SHLQ $5, AX
+	MOVBQZX 42(R9), BX
+	SHLQ $5, BX
+	VMOVDQU (SI)(AX*1), Y5
+	VPXOR (DX)(BX*1), Y5, Y5
+	VPANDN Y5, Y12, Y12
+	VPXOR Y12, Y2, Y2
+	VPANDN Y3, Y0, Y0
+	VPXOR Y1, Y0, Y12
+	VPXOR Y6, Y12, Y12
+	VMOVDQU Y2, 0(SP)
+	VPANDN Y1, Y3, Y2
+	VPXOR Y2, Y4, Y2
+	VMOVDQU Y11, 32(SP)
+	VPANDN Y9, Y2, Y11
+	VPXOR Y11, Y12, Y12
+	VPXOR Y8, Y0, Y8
+	VPANDN Y8, Y6, Y8
+	VPXOR Y8, Y4, Y11
+	VPANDN Y13, Y6, Y13
+	VPXOR Y13, Y2, Y13
+	VPAND Y9, Y13, Y13
+	VPXOR Y13, Y11, Y11
+	VPAND Y5, Y11, Y11
+	VPXOR Y11, Y12, Y12
+	VPANDN Y3, Y1, Y11
+	VPXOR Y11, Y14, Y11
+	VPOR Y1, Y4, Y13
+	VPANDN Y6, Y13, Y13
+	VPANDN Y9, Y13, Y13
+	VPXOR Y13, Y11, Y11
+	VPOR Y1, Y3, Y13
+	VPANDN Y13, Y6, Y13
+	VPXOR Y13, Y7, Y13
+	VPAND Y1, Y3, Y3
+	VPXOR Y3, Y7, Y7
+	VPAND Y6, Y7, Y7
+	VPXOR Y7, Y0, Y7
+	VPAND Y9, Y7, Y7
+	VPXOR Y7, Y13, Y13
+	VPANDN Y5, Y13, Y13
+	VPXOR Y13, Y11, Y11
+	VPANDN Y1, Y14, Y1
+	VPXOR Y1, Y0, Y0
+	VPAND Y6, Y0, Y0
+	VPXOR Y0, Y2, Y2
+	VPAND Y6, Y4, Y4
+	VPXOR Y4, Y3, Y3
+	VPANDN Y9, Y3, Y3
+	VPXOR Y3, Y2, Y2
+	VPOR Y6, Y10, Y10
+	VMOVDQU 32(SP), Y0
+	VPXOR Y10, Y0, Y10
+	VPXOR Y8, Y0, Y0
+	VPAND Y9, Y0, Y0
+	VPXOR Y0, Y10, Y10
+	VPAND Y5, Y10, Y10
+	VPXOR Y10, Y2, Y2
+	VMOVDQU 0(SP), Y0
+	VPXOR 128(DI), Y0, Y0
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y0, Y0
+	VMOVDQU Y0, 128(DI)
+	VPXOR 832(DI), Y12, Y12
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y12, Y12
+	VMOVDQU Y12, 832(DI)
+	VPXOR 448(DI), Y11, Y11
+	VMOVDQU Y11, 448(DI)
+	VPXOR 640(DI), Y2, Y2
+	VPCMPEQB Y15, Y15, Y15
+	VPXOR Y15, Y2, Y2
+	VMOVDQU Y2, 640(DI)
+
+	VZEROUPPER
+	RET
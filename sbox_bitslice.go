@@ -0,0 +1,618 @@
+// Code generated by gen_sbox.go; DO NOT EDIT.
+
+package descrypt
+
+// s1 evaluates DES S-box 1 as a 86-gate boolean circuit.
+func s1(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a6 ^ a5
+	x2 := x1 ^ a2
+	x3 := a3 &^ a2
+	x4 := x2 ^ x3
+	x5 := a6 & a5
+	x6 := a3 &^ x2
+	x7 := x5 ^ x6
+	x8 := x7 & a4
+	x9 := x4 ^ x8
+	x10 := a2 &^ x5
+	x11 := a5 ^ x10
+	x12 := x11 &^ a3
+	x13 := a2 ^ x12
+	x14 := a2 &^ x1
+	x15 := a6 ^ x14
+	x16 := a2 &^ a6
+	x17 := x1 ^ x16
+	x18 := a3 &^ x17
+	x19 := x15 ^ x18
+	x20 := a4 &^ x19
+	x21 := x13 ^ x20
+	x22 := a1 &^ x21
+	x23 := x9 ^ x22
+	x24 := a5 &^ a6
+	x25 := a6 & a2
+	x26 := x24 ^ x25
+	x27 := x26 | a3
+	x28 := x2 ^ x27
+	x29 := x1 | a2
+	x30 := a6 &^ a5
+	x31 := x30 & a3
+	x32 := x29 ^ x31
+	x33 := x32 & a4
+	x34 := x28 ^ x33
+	x35 := a6 | a5
+	x36 := a2 &^ x35
+	x37 := x5 ^ x36
+	x38 := a3 &^ x37
+	x39 := x29 ^ x38
+	x40 := x5 & a2
+	x41 := a5 ^ x40
+	x42 := x5 ^ x16
+	x43 := a3 &^ x42
+	x44 := x41 ^ x43
+	x45 := x44 & a4
+	x46 := x39 ^ x45
+	x47 := x46 & a1
+	x48 := x34 ^ x47
+	x49 := x29 &^ a3
+	x50 := a2 ^ x49
+	x51 := a2 &^ x30
+	x52 := x24 ^ x51
+	x53 := a6 | a2
+	x54 := a3 &^ x53
+	x55 := x52 ^ x54
+	x56 := a4 &^ x55
+	x57 := x50 ^ x56
+	x58 := x36 & a3
+	x59 := x52 ^ x58
+	x60 := x36 ^ x43
+	x61 := x60 & a4
+	x62 := x59 ^ x61
+	x63 := a1 &^ x62
+	x64 := x57 ^ x63
+	x65 := x5 ^ x14
+	x66 := x30 & a2
+	x67 := a5 ^ x66
+	x68 := x67 & a3
+	x69 := x65 ^ x68
+	x70 := x1 & a2
+	x71 := a4 &^ x70
+	x72 := x69 ^ x71
+	x73 := x24 & a2
+	x74 := x35 ^ x73
+	x75 := a3 &^ x11
+	x76 := x74 ^ x75
+	x77 := x35 ^ x16
+	x78 := x77 | a3
+	x79 := x65 ^ x78
+	x80 := a4 &^ x79
+	x81 := x76 ^ x80
+	x82 := x81 & a1
+	x83 := x72 ^ x82
+	return ^x23, ^x48, ^x64, x83
+}
+
+// s2 evaluates DES S-box 2 as a 73-gate boolean circuit.
+func s2(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a1 ^ a5
+	x2 := a5 &^ a1
+	x3 := x2 & a4
+	x4 := x1 ^ x3
+	x5 := x4 ^ a3
+	x6 := a1 & a5
+	x7 := x6 &^ a4
+	x8 := x6 & a3
+	x9 := x7 ^ x8
+	x10 := a6 &^ x9
+	x11 := x5 ^ x10
+	x12 := a4 &^ x2
+	x13 := a3 &^ a1
+	x14 := x12 ^ x13
+	x15 := a1 | a3
+	x16 := x7 ^ x15
+	x17 := a6 &^ x16
+	x18 := x14 ^ x17
+	x19 := x18 & a2
+	x20 := x11 ^ x19
+	x21 := x1 ^ a4
+	x22 := a5 & a4
+	x23 := x22 | a3
+	x24 := a6 &^ x23
+	x25 := x21 ^ x24
+	x26 := a4 &^ x6
+	x27 := x26 ^ x8
+	x28 := x27 &^ a6
+	x29 := a3 ^ x28
+	x30 := a2 &^ x29
+	x31 := x25 ^ x30
+	x32 := x1 ^ x26
+	x33 := a1 | a5
+	x34 := x33 | a4
+	x35 := x34 & a3
+	x36 := x32 ^ x35
+	x37 := a4 &^ a1
+	x38 := x37 & a3
+	x39 := x6 ^ x38
+	x40 := x39 & a6
+	x41 := x36 ^ x40
+	x42 := a1 &^ a4
+	x43 := x6 ^ x42
+	x44 := x43 &^ a3
+	x45 := x6 | a3
+	x46 := x34 ^ x45
+	x47 := x46 & a6
+	x48 := x44 ^ x47
+	x49 := a2 &^ x48
+	x50 := x41 ^ x49
+	x51 := a1 ^ a4
+	x52 := a3 &^ x33
+	x53 := x51 ^ x52
+	x54 := a1 &^ a5
+	x55 := x54 | a3
+	x56 := x3 ^ x55
+	x57 := x56 & a6
+	x58 := x53 ^ x57
+	x59 := x54 ^ x22
+	x60 := a5 & a3
+	x61 := x59 ^ x60
+	x62 := x1 & a4
+	x63 := x6 ^ x62
+	x64 := x33 & a3
+	x65 := x63 ^ x64
+	x66 := a6 &^ x65
+	x67 := x61 ^ x66
+	x68 := x67 & a2
+	x69 := x58 ^ x68
+	return ^x20, ^x31, ^x50, ^x69
+}
+
+// s3 evaluates DES S-box 3 as a 75-gate boolean circuit.
+func s3(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a5 ^ a2
+	x2 := a5 &^ a6
+	x3 := x2 | a2
+	x4 := a6 ^ x3
+	x5 := x4 & a4
+	x6 := x1 ^ x5
+	x7 := a6 & a5
+	x8 := x7 ^ a2
+	x9 := x8 | a4
+	x10 := x3 ^ x9
+	x11 := a3 &^ x10
+	x12 := x6 ^ x11
+	x13 := a6 ^ a2
+	x14 := a4 &^ x4
+	x15 := x13 ^ x14
+	x16 := a3 &^ x9
+	x17 := x15 ^ x16
+	x18 := x17 & a1
+	x19 := x12 ^ x18
+	x20 := a6 | a2
+	x21 := x2 ^ x20
+	x22 := x21 &^ a4
+	x23 := x3 ^ x22
+	x24 := a6 | a5
+	x25 := a2 &^ x24
+	x26 := a5 ^ x25
+	x27 := a2 & a4
+	x28 := x26 ^ x27
+	x29 := a3 &^ x28
+	x30 := x23 ^ x29
+	x31 := x8 & a4
+	x32 := x25 ^ x31
+	x33 := x32 &^ a3
+	x34 := a1 &^ x33
+	x35 := x30 ^ x34
+	x36 := a6 ^ a5
+	x37 := a2 &^ x2
+	x38 := x36 ^ x37
+	x39 := a5 | a2
+	x40 := x24 ^ x39
+	x41 := a4 &^ x40
+	x42 := x38 ^ x41
+	x43 := x21 | a4
+	x44 := x43 & a3
+	x45 := x42 ^ x44
+	x46 := x24 & a2
+	x47 := a6 ^ x46
+	x48 := a6 &^ a5
+	x49 := a2 &^ x48
+	x50 := x24 ^ x49
+	x51 := a4 &^ x50
+	x52 := x47 ^ x51
+	x53 := a2 &^ a6
+	x54 := x48 ^ x53
+	x55 := x54 & a4
+	x56 := x2 ^ x55
+	x57 := x56 & a3
+	x58 := x52 ^ x57
+	x59 := a1 &^ x58
+	x60 := x45 ^ x59
+	x61 := a4 &^ a5
+	x62 := x13 ^ x61
+	x63 := a5 & a3
+	x64 := x62 ^ x63
+	x65 := x36 &^ a4
+	x66 := x25 ^ x65
+	x67 := a6 & a2
+	x68 := x67 &^ a4
+	x69 := x39 ^ x68
+	x70 := a3 &^ x69
+	x71 := x66 ^ x70
+	x72 := a1 &^ x71
+	x73 := x64 ^ x72
+	return ^x19, x35, ^x60, x73
+}
+
+// s4 evaluates DES S-box 4 as a 52-gate boolean circuit.
+func s4(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a5 &^ a3
+	x2 := x1 ^ a1
+	x3 := a3 &^ a5
+	x4 := a1 &^ x3
+	x5 := a4 &^ x4
+	x6 := x2 ^ x5
+	x7 := x1 &^ a1
+	x8 := a3 ^ x7
+	x9 := a3 ^ a5
+	x10 := a1 &^ x9
+	x11 := a5 ^ x10
+	x12 := x11 & a4
+	x13 := x8 ^ x12
+	x14 := x13 & a2
+	x15 := x6 ^ x14
+	x16 := x1 & a1
+	x17 := x9 ^ x16
+	x18 := a5 ^ x4
+	x19 := a4 &^ x18
+	x20 := x17 ^ x19
+	x21 := x9 & a4
+	x22 := x7 ^ x21
+	x23 := a2 &^ x22
+	x24 := x20 ^ x23
+	x25 := a6 &^ x24
+	x26 := x15 ^ x25
+	x27 := x24 &^ a6
+	x28 := x15 ^ x27
+	x29 := x1 | a1
+	x30 := x29 & a4
+	x31 := x17 ^ x30
+	x32 := a1 &^ x1
+	x33 := a3 ^ x10
+	x34 := x33 & a4
+	x35 := x32 ^ x34
+	x36 := a2 &^ x35
+	x37 := x31 ^ x36
+	x38 := a5 ^ x29
+	x39 := a4 &^ x38
+	x40 := x11 ^ x39
+	x41 := x3 & a1
+	x42 := x9 ^ x41
+	x43 := x42 ^ x21
+	x44 := a2 &^ x43
+	x45 := x40 ^ x44
+	x46 := a6 &^ x45
+	x47 := x37 ^ x46
+	x48 := x45 &^ a6
+	x49 := x37 ^ x48
+	return x26, ^x28, ^x47, ^x49
+}
+
+// s5 evaluates DES S-box 5 as a 82-gate boolean circuit.
+func s5(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a6 | a3
+	x2 := x1 &^ a4
+	x3 := a3 ^ x2
+	x4 := a6 & a3
+	x5 := a4 &^ a6
+	x6 := x4 ^ x5
+	x7 := a2 &^ x6
+	x8 := x3 ^ x7
+	x9 := a3 &^ a6
+	x10 := a4 &^ x9
+	x11 := a6 ^ x10
+	x12 := x4 ^ a4
+	x13 := x12 & a2
+	x14 := x11 ^ x13
+	x15 := a5 &^ x14
+	x16 := x8 ^ x15
+	x17 := a6 & a4
+	x18 := x9 ^ x17
+	x19 := a6 ^ a3
+	x20 := a4 &^ x19
+	x21 := x4 ^ x20
+	x22 := x21 & a2
+	x23 := x18 ^ x22
+	x24 := a6 | a4
+	x25 := x24 &^ a2
+	x26 := x21 ^ x25
+	x27 := a5 &^ x26
+	x28 := x23 ^ x27
+	x29 := x28 & a1
+	x30 := x16 ^ x29
+	x31 := x4 | a4
+	x32 := x31 &^ a2
+	x33 := x19 ^ x32
+	x34 := x4 &^ a4
+	x35 := a5 &^ x34
+	x36 := x33 ^ x35
+	x37 := x2 & a2
+	x38 := a6 ^ x20
+	x39 := x38 & a5
+	x40 := x37 ^ x39
+	x41 := a1 &^ x40
+	x42 := x36 ^ x41
+	x43 := x1 & a4
+	x44 := x43 | a2
+	x45 := x12 ^ x44
+	x46 := x19 ^ x10
+	x47 := x1 ^ a4
+	x48 := a2 &^ x47
+	x49 := x46 ^ x48
+	x50 := a5 &^ x49
+	x51 := x45 ^ x50
+	x52 := x46 &^ a2
+	x53 := x4 ^ x52
+	x54 := x4 ^ x17
+	x55 := a2 &^ x54
+	x56 := x46 ^ x55
+	x57 := x56 & a5
+	x58 := x53 ^ x57
+	x59 := a1 &^ x58
+	x60 := x51 ^ x59
+	x61 := x4 & a4
+	x62 := x9 ^ x61
+	x63 := x24 & a2
+	x64 := x62 ^ x63
+	x65 := x1 ^ x10
+	x66 := x19 ^ x17
+	x67 := a2 &^ x66
+	x68 := x65 ^ x67
+	x69 := x68 & a5
+	x70 := x64 ^ x69
+	x71 := a4 &^ x4
+	x72 := x1 ^ x71
+	x73 := a4 &^ a3
+	x74 := x1 ^ x73
+	x75 := a2 &^ x74
+	x76 := x72 ^ x75
+	x77 := x10 ^ x48
+	x78 := x77 & a5
+	x79 := x76 ^ x78
+	x80 := x79 & a1
+	x81 := x70 ^ x80
+	return x30, x42, ^x60, x81
+}
+
+// s6 evaluates DES S-box 6 as a 74-gate boolean circuit.
+func s6(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a1 ^ a4
+	x2 := x1 & a3
+	x3 := x1 &^ a3
+	x4 := a5 &^ x3
+	x5 := x2 ^ x4
+	x6 := a1 | a4
+	x7 := a3 &^ x1
+	x8 := x6 ^ x7
+	x9 := x8 | a5
+	x10 := x9 & a6
+	x11 := x5 ^ x10
+	x12 := x6 &^ a3
+	x13 := x1 ^ x12
+	x14 := a1 & a4
+	x15 := a1 & a3
+	x16 := x14 ^ x15
+	x17 := x16 & a5
+	x18 := x13 ^ x17
+	x19 := x18 & a6
+	x20 := a3 ^ x19
+	x21 := a2 &^ x20
+	x22 := x11 ^ x21
+	x23 := a4 &^ a1
+	x24 := a3 &^ x23
+	x25 := x14 ^ x24
+	x26 := x25 | a5
+	x27 := x12 ^ x26
+	x28 := x15 ^ x17
+	x29 := a6 &^ x28
+	x30 := x27 ^ x29
+	x31 := x16 &^ a5
+	x32 := x23 ^ x31
+	x33 := a1 &^ a4
+	x34 := x33 & a3
+	x35 := a4 ^ x15
+	x36 := x35 & a5
+	x37 := x34 ^ x36
+	x38 := x37 & a6
+	x39 := x32 ^ x38
+	x40 := a2 &^ x39
+	x41 := x30 ^ x40
+	x42 := a1 | a3
+	x43 := x42 & a5
+	x44 := x35 ^ x43
+	x45 := x23 ^ x15
+	x46 := x45 &^ a5
+	x47 := x6 ^ x46
+	x48 := a6 &^ x47
+	x49 := x44 ^ x48
+	x50 := x23 ^ a3
+	x51 := x50 & a5
+	x52 := x42 ^ x51
+	x53 := a5 &^ x16
+	x54 := x53 & a6
+	x55 := x52 ^ x54
+	x56 := x55 & a2
+	x57 := x49 ^ x56
+	x58 := a1 ^ x24
+	x59 := a5 &^ x13
+	x60 := x58 ^ x59
+	x61 := x23 &^ a3
+	x62 := x61 &^ a5
+	x63 := x6 ^ x62
+	x64 := x63 & a6
+	x65 := x60 ^ x64
+	x66 := a4 | a3
+	x67 := x23 &^ a5
+	x68 := x3 ^ x67
+	x69 := x68 & a6
+	x70 := x66 ^ x69
+	x71 := x70 & a2
+	x72 := x65 ^ x71
+	return ^x22, ^x41, x57, x72
+}
+
+// s7 evaluates DES S-box 7 as a 73-gate boolean circuit.
+func s7(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a2 & a4
+	x2 := x1 ^ a5
+	x3 := x2 ^ a6
+	x4 := a2 &^ a4
+	x5 := a4 & a5
+	x6 := x4 ^ x5
+	x7 := x6 &^ a6
+	x8 := a3 &^ x7
+	x9 := x3 ^ x8
+	x10 := a2 | a4
+	x11 := a5 &^ x1
+	x12 := x10 ^ x11
+	x13 := a5 &^ a4
+	x14 := a6 &^ x13
+	x15 := x12 ^ x14
+	x16 := a2 ^ a4
+	x17 := a5 &^ x16
+	x18 := x4 ^ x17
+	x19 := a6 &^ x6
+	x20 := x18 ^ x19
+	x21 := x20 & a3
+	x22 := x15 ^ x21
+	x23 := x22 & a1
+	x24 := x9 ^ x23
+	x25 := x10 ^ a5
+	x26 := x1 & a5
+	x27 := a2 ^ x26
+	x28 := x27 & a6
+	x29 := x25 ^ x28
+	x30 := x5 & a6
+	x31 := a2 ^ x30
+	x32 := x31 & a3
+	x33 := x29 ^ x32
+	x34 := x1 &^ a5
+	x35 := a6 &^ x34
+	x36 := x16 ^ x35
+	x37 := a2 &^ a6
+	x38 := x6 ^ x37
+	x39 := a3 &^ x38
+	x40 := x36 ^ x39
+	x41 := a1 &^ x40
+	x42 := x33 ^ x41
+	x43 := a4 &^ a2
+	x44 := a5 &^ x43
+	x45 := x44 &^ a6
+	x46 := x16 ^ x45
+	x47 := x43 ^ x5
+	x48 := a6 &^ x47
+	x49 := a3 &^ x48
+	x50 := x46 ^ x49
+	x51 := a5 | a6
+	x52 := x34 ^ x51
+	x53 := x10 ^ x17
+	x54 := x53 &^ a6
+	x55 := x47 ^ x54
+	x56 := a3 &^ x55
+	x57 := x52 ^ x56
+	x58 := x57 & a1
+	x59 := x50 ^ x58
+	x60 := a2 ^ x13
+	x61 := x60 ^ x35
+	x62 := x16 ^ x5
+	x63 := a3 &^ x62
+	x64 := x61 ^ x63
+	x65 := x16 & a5
+	x66 := x43 ^ x65
+	x67 := x66 & a6
+	x68 := x62 & a6
+	x69 := x68 & a3
+	x70 := x67 ^ x69
+	x71 := a1 &^ x70
+	x72 := x64 ^ x71
+	return x24, ^x42, x59, x72
+}
+
+// s8 evaluates DES S-box 8 as a 79-gate boolean circuit.
+func s8(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a3 &^ a4
+	x2 := a5 ^ x1
+	x3 := a3 ^ a5
+	x4 := a4 &^ x3
+	x5 := a6 &^ x4
+	x6 := x2 ^ x5
+	x7 := a5 ^ x4
+	x8 := a4 &^ a3
+	x9 := a5 ^ x8
+	x10 := a6 &^ x9
+	x11 := x7 ^ x10
+	x12 := x11 & a2
+	x13 := x6 ^ x12
+	x14 := a3 & a5
+	x15 := x3 & a4
+	x16 := x14 ^ x15
+	x17 := x3 &^ a4
+	x18 := a6 &^ x17
+	x19 := x16 ^ x18
+	x20 := a3 & a4
+	x21 := a3 &^ a5
+	x22 := x21 ^ x4
+	x23 := x22 & a6
+	x24 := x20 ^ x23
+	x25 := x24 & a2
+	x26 := x19 ^ x25
+	x27 := a1 &^ x26
+	x28 := x13 ^ x27
+	x29 := a5 &^ a3
+	x30 := x29 ^ a4
+	x31 := x30 ^ a6
+	x32 := a4 &^ a5
+	x33 := x3 ^ x32
+	x34 := a2 &^ x33
+	x35 := x31 ^ x34
+	x36 := x29 ^ x8
+	x37 := x36 &^ a6
+	x38 := x3 ^ x37
+	x39 := x20 &^ a6
+	x40 := x33 ^ x39
+	x41 := x40 & a2
+	x42 := x38 ^ x41
+	x43 := x42 & a1
+	x44 := x35 ^ x43
+	x45 := a5 &^ a4
+	x46 := x21 ^ x45
+	x47 := x3 | a4
+	x48 := a6 &^ x47
+	x49 := a2 &^ x48
+	x50 := x46 ^ x49
+	x51 := a5 | a4
+	x52 := x51 &^ a6
+	x53 := x14 ^ x52
+	x54 := a5 & a4
+	x55 := x14 ^ x54
+	x56 := x55 & a6
+	x57 := x29 ^ x56
+	x58 := x57 & a2
+	x59 := x53 ^ x58
+	x60 := a1 &^ x59
+	x61 := x50 ^ x60
+	x62 := a4 &^ x21
+	x63 := x29 ^ x62
+	x64 := x63 & a6
+	x65 := x33 ^ x64
+	x66 := x3 & a6
+	x67 := x54 ^ x66
+	x68 := a2 &^ x67
+	x69 := x65 ^ x68
+	x70 := x16 | a6
+	x71 := x17 ^ x70
+	x72 := x17 ^ x37
+	x73 := x72 & a2
+	x74 := x71 ^ x73
+	x75 := x74 & a1
+	x76 := x69 ^ x75
+	return ^x28, ^x44, x61, ^x76
+}
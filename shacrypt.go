@@ -0,0 +1,319 @@
+package descrypt
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// SHA-crypt, Ulrich Drepper's SHA-256 and SHA-512 schemes used by glibc
+// and current Linux shadow files: "$5$" or "$6$", an optional "rounds=N$",
+// a salt of up to 16 characters, "$", and a 43- or 86-character hash.
+// Without a rounds parameter 5000 rounds are used; an explicit count is
+// clamped to [1000, 999999999] and the clamped value is what the hash
+// records.
+
+const (
+	sha256CryptPrefix     = "$5$"
+	sha512CryptPrefix     = "$6$"
+	shaCryptRoundsPrefix  = "rounds="
+	shaCryptSaltMax       = 16
+	shaCryptRoundsDefault = 5000
+	shaCryptRoundsMin     = 1000
+	shaCryptRoundsMax     = 999999999
+)
+
+// shaCryptScheme describes one of the two SHA-crypt variants.
+type shaCryptScheme struct {
+	name    string
+	prefix  string
+	new     func() hash.Hash
+	hashLen int
+	// order lists the digest bytes in the order they are encoded, three
+	// at a time; a trailing partial group is padded with leading zeros.
+	order []int
+}
+
+var sha256Crypt = &shaCryptScheme{
+	name:    "sha256crypt",
+	prefix:  sha256CryptPrefix,
+	new:     sha256.New,
+	hashLen: 43,
+	order: []int{
+		0, 10, 20, 21, 1, 11, 12, 22, 2, 3, 13, 23, 24, 4, 14,
+		15, 25, 5, 6, 16, 26, 27, 7, 17, 18, 28, 8, 9, 19, 29,
+		31, 30,
+	},
+}
+
+var sha512Crypt = &shaCryptScheme{
+	name:    "sha512crypt",
+	prefix:  sha512CryptPrefix,
+	new:     sha512.New,
+	hashLen: 86,
+	order: []int{
+		0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4,
+		47, 5, 26, 6, 27, 48, 28, 49, 7, 50, 8, 29, 9, 30, 51,
+		31, 52, 10, 53, 11, 32, 12, 33, 54, 34, 55, 13, 56, 14, 35,
+		15, 36, 57, 37, 58, 16, 59, 17, 38, 18, 39, 60, 40, 61, 19,
+		62, 20, 41, 63,
+	},
+}
+
+// SHA256CryptHash computes the SHA-256-crypt hash for a password and a setting
+// ("$5$", an optional "rounds=N$", and a salt of up to 16 chars)
+// Characters after the sixteenth of the salt, or after a "$" ending it, are ignored
+// Returns the setting with any rounds clamped, "$" and a 43-character hash
+func SHA256CryptHash(password, setting string) (string, error) {
+	return sha256Crypt.hash(password, setting)
+}
+
+// SHA256CryptPasswordVerify verifies a password against a SHA-256-crypt hash ("$5$[rounds=N$]salt$hash")
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a SHA-256-crypt
+// hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func SHA256CryptPasswordVerify(inputPassword string, storedHash string) error {
+	return sha256Crypt.verify(inputPassword, storedHash)
+}
+
+// SHA512CryptHash computes the SHA-512-crypt hash for a password and a setting
+// ("$6$", an optional "rounds=N$", and a salt of up to 16 chars)
+// Characters after the sixteenth of the salt, or after a "$" ending it, are ignored
+// Returns the setting with any rounds clamped, "$" and an 86-character hash
+func SHA512CryptHash(password, setting string) (string, error) {
+	return sha512Crypt.hash(password, setting)
+}
+
+// SHA512CryptPasswordVerify verifies a password against a SHA-512-crypt hash ("$6$[rounds=N$]salt$hash")
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a SHA-512-crypt
+// hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func SHA512CryptPasswordVerify(inputPassword string, storedHash string) error {
+	return sha512Crypt.verify(inputPassword, storedHash)
+}
+
+// shaSetting is a parsed SHA-crypt setting.
+type shaSetting struct {
+	salt   string
+	rounds int
+	// custom is set if the setting had a rounds parameter, which the
+	// hash then repeats.
+	custom bool
+	// end is the offset of the end of the salt in the setting.
+	end int
+}
+
+// appendSetting appends the canonical form of s, ending in "$", to dst.
+func (c *shaCryptScheme) appendSetting(dst []byte, s *shaSetting) []byte {
+	dst = append(dst, c.prefix...)
+	if s.custom {
+		dst = append(dst, shaCryptRoundsPrefix...)
+		dst = strconv.AppendInt(dst, int64(s.rounds), 10)
+		dst = append(dst, '$')
+	}
+	dst = append(dst, s.salt...)
+	return append(dst, '$')
+}
+
+func (c *shaCryptScheme) hash(password, setting string) (string, error) {
+	if err := checkMethod(c.name); err != nil {
+		return "", err
+	}
+	s, err := c.parseSetting(setting)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, 0, s.end+len(shaCryptRoundsPrefix)+10+c.hashLen)
+	out = c.appendSetting(out, &s)
+	return string(c.crypt(out, password, &s)), nil
+}
+
+func (c *shaCryptScheme) verify(inputPassword, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	s, pe := c.parseHash(hash)
+	if pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+	got := c.appendSetting(make([]byte, 0, len(hash)), &s)
+	got = c.crypt(got, inputPassword, &s)
+	if subtle.ConstantTimeCompare(got, []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseHash parses a stored SHA-crypt hash, reporting any flaw as a
+// *ParseError wrapping ErrMalformedHash.
+func (c *shaCryptScheme) parseHash(hash string) (shaSetting, *ParseError) {
+	s, err := c.parseSetting(hash)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return s, &ParseError{Pos: pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	n := s.end
+	if pe = checkTail(hash, n, c.hashLen); pe != nil {
+		return s, pe
+	}
+
+	// A rounds parameter out of range or with leading zeros can never be
+	// produced by hashing, so report it rather than a mismatch.
+	if string(c.appendSetting(make([]byte, 0, n+1), &s)) != hash[:n+1] {
+		pos := len(c.prefix) + len(shaCryptRoundsPrefix)
+		return s, &ParseError{Pos: pos, Char: hash[pos], Err: ErrMalformedHash}
+	}
+	return s, nil
+}
+
+// parseSetting parses a SHA-crypt setting as glibc does: the prefix, an
+// optional "rounds=N$" with N read by strtoul and clamped to the allowed
+// range, and a salt of up to 16 bytes ending at the first "$". A "rounds="
+// not followed by a number and "$" is part of the salt. The salt may hold
+// any byte but NUL, which C never sees, and ':' and newline, which
+// libxcrypt rejects as they would break a shadow file.
+func (c *shaCryptScheme) parseSetting(setting string) (shaSetting, error) {
+	s := shaSetting{rounds: shaCryptRoundsDefault}
+	for i := 0; i < len(c.prefix); i++ {
+		if i >= len(setting) {
+			return s, &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		if setting[i] != c.prefix[i] {
+			return s, &ParseError{Pos: i, Char: setting[i], Err: ErrInvalidSalt}
+		}
+	}
+	start := len(c.prefix)
+	if strings.HasPrefix(setting[start:], shaCryptRoundsPrefix) {
+		if rounds, end := strtoul(setting, start+len(shaCryptRoundsPrefix)); end < len(setting) && setting[end] == '$' {
+			s.rounds = max(shaCryptRoundsMin, min(rounds, shaCryptRoundsMax))
+			s.custom = true
+			start = end + 1
+		}
+	}
+	salt := setting[start:]
+	n := min(len(salt), shaCryptSaltMax)
+	for i := 0; i < n; i++ {
+		switch salt[i] {
+		case '$':
+			n = i
+		case 0, ':', '\n':
+			return s, &ParseError{Pos: start + i, Char: salt[i], Err: ErrInvalidSalt}
+		}
+	}
+	s.salt = salt[:n]
+	s.end = start + n
+	return s, nil
+}
+
+// strtoul reads a number at s[i:] as C's strtoul does in base 10, after
+// optional white space and sign, saturating at a value above any rounds
+// count. It returns the number and the offset after it, or i itself if
+// there are no digits.
+func strtoul(s string, i int) (int, int) {
+	j := i
+	for j < len(s) && strings.IndexByte(" \t\n\v\f\r", s[j]) >= 0 {
+		j++
+	}
+	neg := false
+	if j < len(s) && (s[j] == '+' || s[j] == '-') {
+		neg = s[j] == '-'
+		j++
+	}
+	digits := j
+	n := 0
+	for ; j < len(s) && '0' <= s[j] && s[j] <= '9'; j++ {
+		n = min(n*10+int(s[j]-'0'), shaCryptRoundsMax+1)
+	}
+	if j == digits {
+		return 0, i
+	}
+	// Negating a nonzero unsigned long leaves it huge.
+	if neg && n != 0 {
+		n = shaCryptRoundsMax + 1
+	}
+	return n, j
+}
+
+// crypt appends the encoded SHA-crypt hash of password under s to dst.
+func (c *shaCryptScheme) crypt(dst []byte, password string, s *shaSetting) []byte {
+	pw, salt := []byte(password), []byte(s.salt)
+	h := c.new()
+	size := h.Size()
+
+	h.Write(pw)
+	h.Write(salt)
+	h.Write(pw)
+	alt := h.Sum(nil)
+
+	h.Reset()
+	h.Write(pw)
+	h.Write(salt)
+	for n := len(pw); n > 0; n -= size {
+		h.Write(alt[:min(n, size)])
+	}
+	for n := len(pw); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(alt)
+		} else {
+			h.Write(pw)
+		}
+	}
+	sum := h.Sum(nil)
+
+	// P and S are the password and salt, each replaced by bytes of a
+	// digest of its own repetitions.
+	h.Reset()
+	for range pw {
+		h.Write(pw)
+	}
+	p := repeatDigest(h.Sum(alt[:0]), len(pw))
+	h.Reset()
+	for i := 0; i < 16+int(sum[0]); i++ {
+		h.Write(salt)
+	}
+	st := repeatDigest(h.Sum(nil), len(salt))
+
+	for i := 0; i < s.rounds; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write(st)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(sum)
+		} else {
+			h.Write(p)
+		}
+		sum = h.Sum(sum[:0])
+	}
+
+	o := c.order
+	for ; len(o) >= 3; o = o[3:] {
+		dst = appendA64(dst, uint32(sum[o[0]])<<16|uint32(sum[o[1]])<<8|uint32(sum[o[2]]), 4)
+	}
+	if len(o) == 2 {
+		return appendA64(dst, uint32(sum[o[0]])<<8|uint32(sum[o[1]]), 3)
+	}
+	return appendA64(dst, uint32(sum[o[0]]), 2)
+}
+
+// repeatDigest returns the first n bytes of digest repeated end to end.
+func repeatDigest(digest []byte, n int) []byte {
+	out := make([]byte, n)
+	for i := 0; i < n; i += len(digest) {
+		copy(out[i:], digest)
+	}
+	return out
+}
@@ -0,0 +1,175 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// SHA-crypt vectors from Ulrich Drepper's specification.
+var shaCryptTests = []struct {
+	setting  string
+	password string
+	hash     string
+}{
+	{"$5$saltstring", "Hello world!", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+	{"$5$rounds=10000$saltstringsaltstring", "Hello world!", "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+	{"$5$rounds=5000$toolongsaltstring", "This is just a test", "$5$rounds=5000$toolongsaltstrin$Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5"},
+	{"$5$rounds=1400$anotherlongsaltstring", "a very much longer text to encrypt.  This one even stretches over morethan one line.", "$5$rounds=1400$anotherlongsalts$Rx.j8H.h8HjEDGomFU8bDkXm3XIUnzyxf12oP84Bnq1"},
+	{"$5$rounds=77777$short", "we have a short salt string but not a short password", "$5$rounds=77777$short$JiO1O3ZpDAxGJeaDIuqCoEFysAe1mZNJRs3pw0KQRd/"},
+	{"$5$rounds=123456$asaltof16chars..", "a short string", "$5$rounds=123456$asaltof16chars..$gP3VQ/6X7UUEW3HkBn2w1/Ptq2jxPyzV/cZKmF/wJvD"},
+	{"$5$rounds=10$roundstoolow", "the minimum number is still observed", "$5$rounds=1000$roundstoolow$yfvwcWrQ8l/K0DAWyuPMDNHpIVlTQebY9l/gL972bIC"},
+	{"$6$saltstring", "Hello world!", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+	{"$6$rounds=10000$saltstringsaltstring", "Hello world!", "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+	{"$6$rounds=5000$toolongsaltstring", "This is just a test", "$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0"},
+	{"$6$rounds=1400$anotherlongsaltstring", "a very much longer text to encrypt.  This one even stretches over morethan one line.", "$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1"},
+	{"$6$rounds=77777$short", "we have a short salt string but not a short password", "$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+	{"$6$rounds=123456$asaltof16chars..", "a short string", "$6$rounds=123456$asaltof16chars..$BtCwjqMJGx5hrJhZywWvt0RLE8uZ4oPwcelCjmw2kSYu.Ec6ycULevoBK25fs2xXgMNrCzIMVcgEJAstJeonj1"},
+	{"$6$rounds=10$roundstoolow", "the minimum number is still observed", "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+}
+
+func TestSHACryptHash(t *testing.T) {
+	for _, tc := range shaCryptTests {
+		hash, verify := SHA256CryptHash, SHA256CryptPasswordVerify
+		if tc.setting[1] == '6' {
+			hash, verify = SHA512CryptHash, SHA512CryptPasswordVerify
+		}
+		got, err := hash(tc.password, tc.setting)
+		if err != nil {
+			t.Fatalf("hash(%q) error = %v", tc.setting, err)
+		}
+		if got != tc.hash {
+			t.Errorf("hash(%q, %q) = %v, want %v", tc.password, tc.setting, got, tc.hash)
+		}
+		if err := verify(tc.password, tc.hash); err != nil {
+			t.Errorf("verify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := verify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("verify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := verify(tc.password+"x", tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("verify(%q, %q) error = %v, want ErrMismatch", tc.password+"x", tc.hash, err)
+		}
+	}
+
+	for _, tc := range []struct{ setting, want string }{
+		{"$5$saltstring$xyz", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		{"$5$rounds=0010$saltstring", "$5$rounds=1000$saltstring$z/y8l95GSjij6uHx2xAJer7YCODLtrhIxItWC13D4g5"},
+		{"$5$rounds=99999999999$saltstring", "$5$rounds=999999999$saltstring$"},
+		{"$6$saltstring$", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+	} {
+		hash := SHA256CryptHash
+		if tc.setting[1] == '6' {
+			hash = SHA512CryptHash
+		}
+		if strings.HasPrefix(tc.setting, "$5$rounds=9") {
+			// Only check the clamped setting; the hash itself would take
+			// a billion rounds.
+			s, err := sha256Crypt.parseSetting(tc.setting)
+			if got := string(sha256Crypt.appendSetting(nil, &s)); err != nil || got != tc.want {
+				t.Errorf("parseSetting(%q) = %v, %v, want %v", tc.setting, got, err, tc.want)
+			}
+			continue
+		}
+		if got, err := hash("Hello world!", tc.setting); got != tc.want {
+			t.Errorf("hash(%q) = %v, %v, want %v", tc.setting, got, err, tc.want)
+		}
+	}
+}
+
+func TestSHACryptGlibcSettings(t *testing.T) {
+	// glibc reads "rounds=" with strtoul and, without a number and "$",
+	// takes it as salt; the salt may hold any byte but "$".
+	testCases := []struct {
+		setting string
+		salt    string
+		rounds  int
+	}{
+		{"$5$rounds=$abc", "abc", shaCryptRoundsMin},
+		{"$5$rounds= +2000$abc", "abc", 2000},
+		{"$5$rounds=-5$abc", "abc", shaCryptRoundsMax},
+		{"$5$rounds=-0$abc", "abc", shaCryptRoundsMin},
+		{"$5$rounds=12x$abc", "rounds=12x", shaCryptRoundsDefault},
+		{"$5$rounds=5000", "rounds=5000", shaCryptRoundsDefault},
+		{"$5$rounds=$", "", shaCryptRoundsMin},
+		{"$5$sa!t ~\x80\xff$", "sa!t ~\x80\xff", shaCryptRoundsDefault},
+	}
+	for _, tc := range testCases {
+		s, err := sha256Crypt.parseSetting(tc.setting)
+		if err != nil || s.salt != tc.salt || s.rounds != tc.rounds {
+			t.Errorf("parseSetting(%q) = salt %q rounds %d, %v, want salt %q rounds %d", tc.setting, s.salt, s.rounds, err, tc.salt, tc.rounds)
+		}
+	}
+
+	for _, setting := range []string{"$5$rounds=12x$abc", "$5$sa!t ~\x80\xff", "$6$rounds=1000$\x01;*\\"} {
+		hash, err := Crypt("password", setting)
+		if err != nil {
+			t.Fatalf("Crypt(%q) error = %v", setting, err)
+		}
+		if err := Verify("password", hash); err != nil {
+			t.Errorf("Verify(%q) error = %v", hash, err)
+		}
+	}
+}
+
+func TestSHACryptErrors(t *testing.T) {
+	settings := []struct {
+		setting string
+		want    error
+	}{
+		{"$5", ErrSaltTooShort},
+		{"$1$abc", ErrInvalidSalt},
+		{"$5$ab:d", ErrInvalidSalt},
+		{"$6$ab\nd", ErrInvalidSalt},
+		{"$5$ab\x00d", ErrInvalidSalt},
+		{"$6$rounds=5000$a:b", ErrInvalidSalt},
+	}
+	for _, tc := range settings {
+		hash := SHA256CryptHash
+		if tc.setting[1] == '6' {
+			hash = SHA512CryptHash
+		}
+		if _, err := hash("password", tc.setting); !errors.Is(err, tc.want) {
+			t.Errorf("hash(%q) error = %v, want %v", tc.setting, err, tc.want)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"$5$saltstring", 13, 0},
+		{"$5$saltstringsaltstr$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", 19, 'r'},
+		{"$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc", 56, 0},
+		{"$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5x", 57, 'x'},
+		{"$5$saltstring$5B8vYYiY_CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", 22, '_'},
+		{"$5$rounds=10$roundstoolow$yfvwcWrQ8l/K0DAWyuPMDNHpIVlTQebY9l/gL972bIC", 10, '1'},
+		{"$5$rounds=01000$roundstoolow$yfvwcWrQ8l/K0DAWyuPMDNHpIVlTQebY9l/gL972bIC", 10, '0'},
+		{"{CRYPT}$5$rounds=1x$abc", 23, 0},
+	}
+	for _, tc := range testCases {
+		err := SHA256CryptPasswordVerify("password", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("SHA256CryptPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("SHA256CryptPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
+
+func BenchmarkSHA256CryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SHA256CryptHash("password", "$5$saltstring")
+	}
+}
+
+func BenchmarkSHA512CryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SHA512CryptHash("password", "$6$saltstring")
+	}
+}
@@ -0,0 +1,103 @@
+package descrypt
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// welchT measures fn on two input classes in random interleaved order, in
+// the style of dudect, and returns Welch's t-statistic for the difference
+// in mean running time. The slowest tenth of all measurements is dropped
+// to keep scheduler and GC noise out of the statistic.
+func welchT(n int, fn func(class int)) float64 {
+	rng := rand.New(rand.NewSource(1))
+	classes := make([]int, 2*n)
+	for i := range classes {
+		classes[i] = i & 1
+	}
+	rng.Shuffle(len(classes), func(i, j int) { classes[i], classes[j] = classes[j], classes[i] })
+
+	times := make([]float64, len(classes))
+	for i, c := range classes {
+		start := time.Now()
+		fn(c)
+		times[i] = float64(time.Since(start))
+	}
+
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	limit := sorted[len(sorted)*9/10]
+
+	var count [2]float64
+	var mean [2]float64
+	var m2 [2]float64
+	for i, c := range classes {
+		x := times[i]
+		if x > limit {
+			continue
+		}
+		count[c]++
+		d := x - mean[c]
+		mean[c] += d / count[c]
+		m2[c] += d * (x - mean[c])
+	}
+	v0 := m2[0] / (count[0] - 1)
+	v1 := m2[1] / (count[1] - 1)
+	return (mean[0] - mean[1]) / math.Sqrt(v0/count[0]+v1/count[1])
+}
+
+// tThreshold is far above what a constant-time function produces on a
+// noisy machine, yet well below the statistic an early exit or an extra
+// allocation yields with this many samples.
+const tThreshold = 10
+
+func TestVerifyTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test skipped in short mode")
+	}
+	const samples = 20000
+	h := NewHasher("SecretPassword123")
+
+	testCases := []struct {
+		name   string
+		hashes [2]string
+	}{
+		// A matching hash against one that differs in its last character.
+		{"match", [2]string{"rq/N3gSWdwWeA", "rq/N3gSWdwWeB"}},
+		// Same salt; the hash differs in the first character or only
+		// in the last.
+		{"matching prefix length", [2]string{"rqAAAAAAAAAAA", "rq/N3gSWdwWeB"}},
+		// Well-formed mismatch against a hash of the wrong length, and
+		// against one with an invalid salt character.
+		{"malformed length", [2]string{"rqnO5.MEhjGLo", "rqnO5.MEhjGL"}},
+		{"malformed salt", [2]string{"rqnO5.MEhjGLo", "r!nO5.MEhjGLo"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tStat := welchT(samples, func(class int) {
+				h.Verify(tc.hashes[class])
+			})
+			if math.Abs(tStat) > tThreshold {
+				t.Errorf("Verify() timing depends on the stored hash: t = %.1f", tStat)
+			}
+
+			tStat = welchT(samples, func(class int) {
+				DESPasswordVerify("SecretPassword123", tc.hashes[class])
+			})
+			if math.Abs(tStat) > tThreshold {
+				t.Errorf("DESPasswordVerify() timing depends on the stored hash: t = %.1f", tStat)
+			}
+
+			tStat = welchT(samples, func(class int) {
+				VerifyDESCrypt([]byte("SecretPassword123"), []byte(tc.hashes[class]))
+			})
+			if math.Abs(tStat) > tThreshold {
+				t.Errorf("VerifyDESCrypt() timing depends on the stored hash: t = %.1f", tStat)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package descrypt
+
+import "strings"
+
+// CryptOption configures Crypt and Verify. FailureTokens affects only
+// hashing.
+type CryptOption func(*cryptOptions)
+
+type cryptOptions struct {
+	failureTokens  bool
+	compat         Compat
+	rejectOverlong bool
+}
+
+// FailureTokens makes Crypt report an unusable setting as libxcrypt's and
+// PHP's crypt do: instead of an error it returns the failure token "*0",
+// or "*1" if the setting begins with "*0", so that the token never equals
+// the setting it came from. A stored token
+// therefore never matches the result of hashing against it, and since "*"
+// is outside itoa64 no verify function accepts a token as a hash.
+func FailureTokens() CryptOption {
+	return func(o *cryptOptions) {
+		o.failureTokens = true
+	}
+}
+
+// finish returns the result of hashing under setting, with err replaced
+// by a failure token if the options ask for one.
+func (o *cryptOptions) finish(setting, hash string, err error) (string, error) {
+	if err != nil && o.failureTokens {
+		return failureToken(setting), nil
+	}
+	return hash, err
+}
+
+// desCrypt computes the DES crypt(3) hash of password under salt.
+func (o *cryptOptions) desCrypt(password, salt string) (string, error) {
+	if err := o.checkLength(password); err != nil {
+		return "", err
+	}
+	if o.compat != 0 {
+		return desCryptCompat(password, salt, o.compat)
+	}
+	return NewHasher(password).HashWithSalt(salt)
+}
+
+// desVerify checks password against a traditional DES crypt hash.
+func (o *cryptOptions) desVerify(password, storedHash string) error {
+	if err := o.checkLength(password); err != nil {
+		return err
+	}
+	if o.compat != 0 {
+		return verifyDESCompat(password, storedHash, o.compat)
+	}
+	return NewHasher(password).Verify(storedHash)
+}
+
+// checkLength returns ErrPasswordTooLong if the options reject password
+// as longer than traditional DES crypt uses.
+func (o *cryptOptions) checkLength(password string) error {
+	if !o.rejectOverlong {
+		return nil
+	}
+	if o.compat != 0 {
+		if i := strings.IndexByte(password, 0); i >= 0 {
+			password = password[:i]
+		}
+	}
+	if len(password) > desKeyLen {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+func newCryptOptions(opts []CryptOption) *cryptOptions {
+	var o cryptOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// failureToken returns the failure token for setting.
+func failureToken(setting string) string {
+	if strings.HasPrefix(setting, "*0") {
+		return "*1"
+	}
+	return "*0"
+}
@@ -0,0 +1,57 @@
+package descrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFailureTokens(t *testing.T) {
+	testCases := []struct {
+		setting string
+		want    string
+	}{
+		{"", "*0"},
+		{"a", "*0"},
+		{"a!", "*0"},
+		{"*0", "*1"},
+		{"*0abc", "*1"},
+		{"*1", "*0"},
+		{"*", "*0"},
+	}
+	for _, tc := range testCases {
+		if got, err := Crypt("password", tc.setting, FailureTokens()); got != tc.want || err != nil {
+			t.Errorf("Crypt(%q, FailureTokens()) = %v, %v, want %v", tc.setting, got, err, tc.want)
+		}
+	}
+
+	for _, setting := range []string{"$9$abc", "$1$a!c", "$2b$99$abcdefghijklmnopqrstu.", "_...."} {
+		if got, err := Crypt("password", setting, FailureTokens()); got != "*0" || err != nil {
+			t.Errorf("Crypt(%q, FailureTokens()) = %v, %v, want *0", setting, got, err)
+		}
+	}
+
+	// Valid settings hash as usual, and errors are unchanged without the option.
+	if got, err := Crypt("SecretPassword123", "rq", FailureTokens()); got != "rq/N3gSWdwWeA" || err != nil {
+		t.Errorf("Crypt() = %v, %v with FailureTokens()", got, err)
+	}
+	if _, err := Crypt("password", "*0"); !errors.Is(err, ErrInvalidSalt) {
+		t.Errorf("Crypt(%q) error = %v, want ErrInvalidSalt", "*0", err)
+	}
+}
+
+func TestFailureTokensNeverVerify(t *testing.T) {
+	for _, token := range []string{"*0", "*1", "{CRYPT}*0"} {
+		for _, password := range []string{"", "*0", "*1", "password"} {
+			if err := Verify(password, token); !errors.Is(err, ErrMalformedHash) {
+				t.Errorf("Verify(%q, %q) error = %v, want ErrMalformedHash", password, token, err)
+			}
+			if err := DESPasswordVerify(password, token); !errors.Is(err, ErrMalformedHash) {
+				t.Errorf("DESPasswordVerify(%q, %q) error = %v, want ErrMalformedHash", password, token, err)
+			}
+			// Hashing against a stored token gives the other token.
+			if got, _ := Crypt(password, token, FailureTokens()); got == token {
+				t.Errorf("Crypt(%q, %q, FailureTokens()) = %v, equal to the setting", password, token, got)
+			}
+		}
+	}
+}
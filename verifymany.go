@@ -0,0 +1,116 @@
+package descrypt
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Credential is a password to check against a stored DES crypt hash.
+type Credential struct {
+	Password string
+	Hash     string
+}
+
+// Outcome classifies the result of verifying one Credential.
+type Outcome int
+
+const (
+	// Canceled means the credential was not checked because the context
+	// was done first.
+	Canceled Outcome = iota
+	// Match means the password matches the hash.
+	Match
+	// Mismatch means the hash is well-formed but the password is wrong.
+	Mismatch
+	// Malformed means the stored hash is not a valid DES crypt hash.
+	Malformed
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Canceled:
+		return "canceled"
+	case Match:
+		return "match"
+	case Mismatch:
+		return "mismatch"
+	case Malformed:
+		return "malformed"
+	}
+	return "unknown"
+}
+
+// Result is the outcome of verifying one Credential. Err is nil for a
+// Match, the DESPasswordVerify error for a Mismatch or Malformed hash, and
+// the context's error for a Canceled item.
+type Result struct {
+	Outcome Outcome
+	Err     error
+}
+
+// Option configures VerifyMany.
+type Option func(*verifyOptions)
+
+type verifyOptions struct {
+	workers int
+}
+
+// Workers sets the number of goroutines VerifyMany runs. It defaults to
+// GOMAXPROCS; values below 1 are treated as 1.
+func Workers(n int) Option {
+	return func(o *verifyOptions) {
+		o.workers = n
+	}
+}
+
+// VerifyMany checks each credential with DESPasswordVerify on a bounded
+// pool of goroutines and returns one Result per item, in the order of
+// items. Once ctx is done, workers stop picking up new items, and the
+// items they did not reach are reported as Canceled.
+func VerifyMany(ctx context.Context, items []Credential, opts ...Option) []Result {
+	o := verifyOptions{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	workers := max(min(o.workers, len(items)), 1)
+
+	results := make([]Result, len(items))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(items) {
+					return
+				}
+				results[i] = verifyResult(DESPasswordVerify(items[i].Password, items[i].Hash))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if results[i].Outcome == Canceled {
+				results[i].Err = err
+			}
+		}
+	}
+	return results
+}
+
+func verifyResult(err error) Result {
+	switch {
+	case err == nil:
+		return Result{Outcome: Match}
+	case errors.Is(err, ErrMismatch):
+		return Result{Outcome: Mismatch, Err: err}
+	}
+	return Result{Outcome: Malformed, Err: err}
+}
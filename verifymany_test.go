@@ -0,0 +1,89 @@
+package descrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestVerifyMany(t *testing.T) {
+	items := []Credential{
+		{"SecretPassword123", "rq/N3gSWdwWeA"},
+		{"WrongPassword", "rq/N3gSWdwWeA"},
+		{"TestPassword123", "{CRYPT}pnA3klLBJ.CRU"},
+		{"password", "ab1xQWzQ9Qf"},
+		{"password", "a!1xQWzQ9Qf8w"},
+	}
+	want := []Outcome{Match, Mismatch, Match, Malformed, Malformed}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			results := VerifyMany(context.Background(), items, Workers(workers))
+			if len(results) != len(items) {
+				t.Fatalf("VerifyMany() returned %d results, want %d", len(results), len(items))
+			}
+			for i, r := range results {
+				if r.Outcome != want[i] {
+					t.Errorf("VerifyMany()[%d].Outcome = %v, want %v", i, r.Outcome, want[i])
+				}
+				if (r.Err == nil) != (want[i] == Match) {
+					t.Errorf("VerifyMany()[%d].Err = %v", i, r.Err)
+				}
+			}
+		})
+	}
+
+	if results := VerifyMany(context.Background(), nil); len(results) != 0 {
+		t.Errorf("VerifyMany(nil) = %v, want no results", results)
+	}
+}
+
+func TestVerifyManyCanceled(t *testing.T) {
+	items := make([]Credential, 100)
+	for i := range items {
+		items[i] = Credential{"SecretPassword123", "rq/N3gSWdwWeA"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, r := range VerifyMany(ctx, items) {
+		if r.Outcome != Canceled || !errors.Is(r.Err, context.Canceled) {
+			t.Fatalf("VerifyMany()[%d] = %v, %v, want canceled", i, r.Outcome, r.Err)
+		}
+	}
+}
+
+func TestVerifyManyDeadline(t *testing.T) {
+	items := make([]Credential, 200000)
+	for i := range items {
+		items[i] = Credential{"SecretPassword123", "rq/N3gSWdwWeA"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := VerifyMany(ctx, items, Workers(2))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("VerifyMany() took %v after the deadline", elapsed)
+	}
+
+	var matched, canceled int
+	for i, r := range results {
+		switch r.Outcome {
+		case Match:
+			matched++
+		case Canceled:
+			canceled++
+			if !errors.Is(r.Err, context.DeadlineExceeded) {
+				t.Fatalf("VerifyMany()[%d].Err = %v, want deadline exceeded", i, r.Err)
+			}
+		default:
+			t.Fatalf("VerifyMany()[%d].Outcome = %v", i, r.Outcome)
+		}
+	}
+	if canceled == 0 {
+		t.Errorf("VerifyMany() verified all %d items before the deadline", matched)
+	}
+}
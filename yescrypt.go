@@ -0,0 +1,678 @@
+package descrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+// yescrypt, Solar Designer's scrypt derivative and the default hash of
+// current Debian and Fedora releases, in libxcrypt's "$y$" encoding:
+// "$y$", the parameters, "$", the salt, "$", and a 43-character hash. The
+// parameters are variable-length itoa64 numbers: the flavor, log2(N), r,
+// and optionally a bitmask followed by p and t. The salt is itoa64-encoded
+// bytes. Only the flavors libxcrypt accepts without a ROM are supported:
+// the default read-write mode ("j"), write-once ("/") and classic scrypt
+// (".").
+
+const (
+	yescryptPrefix  = "$y$"
+	yescryptHashLen = 43
+	yescryptSaltMax = 64
+
+	// yescryptMaxMemory bounds the bytes a setting may make us allocate
+	// for V, B and the S-boxes together: 1 GiB for V, what libxcrypt's
+	// highest cost uses, plus 1 MiB. A hostile stored hash can still make
+	// each verification allocate this much, so callers verifying many
+	// untrusted hashes at once should bound how many run in parallel.
+	// libxcrypt's default uses 16 MiB.
+	yescryptMaxMemory = 1<<30 + 1<<20
+	// yescryptMaxTime bounds t, so that hashing takes at most about five
+	// times as long as a single pass over V. libxcrypt's settings use 0.
+	yescryptMaxTime = 4
+)
+
+// yescrypt flags, as in yescrypt.h.
+const (
+	yescryptWORM     = 0x001
+	yescryptRW       = 0x002
+	yescryptDefaults = 0x0b6 // RW, 6 rounds, gather 4, simple 2, 12 KiB S-boxes
+	yescryptPrehash  = 0x10000000
+)
+
+// pwxform settings of the default flavor.
+const (
+	pwxSimple = 2
+	pwxGather = 4
+	pwxRounds = 6
+	sWidth    = 8
+	// sWords is the number of 64-bit words in each of S0, S1 and S2.
+	sWords = (1 << sWidth) * pwxSimple
+	sBytes = 3 * sWords * 8
+	sMask  = ((1 << sWidth) - 1) * pwxSimple * 8
+)
+
+// yescryptParams are the parameters of a "$y$" setting.
+type yescryptParams struct {
+	flags   uint32
+	logN    uint
+	r, p, t uint32
+}
+
+// memory returns the bytes hashing under y allocates: 128*r for each of
+// the p blocks of B and the N blocks of V, plus S-boxes per thread in the
+// read-write mode. r*N and r*p must already be known to be in range.
+func (y yescryptParams) memory() uint64 {
+	m := 128 * (uint64(y.r)*uint64(y.p) + uint64(y.r)<<y.logN)
+	if y.flags&yescryptRW != 0 {
+		m += uint64(y.p) * sBytes
+	}
+	return m
+}
+
+// YescryptHash computes the yescrypt hash for a password and a setting
+// ("$y$" + parameters + "$" + encoded salt); anything after a further "$" is ignored
+// Returns the setting, "$" and a 43-character hash
+func YescryptHash(password, setting string) (string, error) {
+	if err := checkMethod("yescrypt"); err != nil {
+		return "", err
+	}
+	params, salt, end, err := parseYescryptSetting(setting)
+	if err != nil {
+		return "", err
+	}
+	sum := yescrypt([]byte(password), salt, &params)
+	out := make([]byte, 0, end+1+yescryptHashLen)
+	out = append(out, setting[:end]...)
+	out = append(out, '$')
+	return string(appendYescrypt64(out, sum[:])), nil
+}
+
+// YescryptPasswordVerify verifies a password against a yescrypt hash ("$y$params$salt$hash")
+// Returns nil if the password matches, ErrMismatch if it does not, or a
+// *ParseError wrapping ErrMalformedHash if storedHash is not a yescrypt
+// hash. A "{CRYPT}" prefix is accepted. The final comparison is
+// constant-time.
+func YescryptPasswordVerify(inputPassword string, storedHash string) error {
+	hash, offset := trimCryptPrefix(storedHash)
+	params, salt, n, err := parseYescryptSetting(hash)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Pos: offset + pe.Pos, Char: pe.Char, EOF: pe.EOF, Err: ErrMalformedHash}
+	}
+	if n == len(hash) {
+		return &ParseError{Pos: offset + n, EOF: true, Err: ErrMalformedHash}
+	}
+	if pe = checkEncoded(hash, n+1, n+1+yescryptHashLen); pe != nil {
+		pe.Pos += offset
+		return pe
+	}
+
+	sum := yescrypt([]byte(inputPassword), salt, &params)
+	got := make([]byte, 0, len(hash))
+	got = append(got, hash[:n+1]...)
+	got = appendYescrypt64(got, sum[:])
+	if subtle.ConstantTimeCompare(got, []byte(hash)) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseYescryptSetting returns the parameters and decoded salt of a "$y$"
+// setting, and the offset of the end of the salt, which runs to the last
+// "$" of the setting or to its end.
+func parseYescryptSetting(setting string) (y yescryptParams, salt []byte, end int, err error) {
+	for i := 0; i < len(yescryptPrefix); i++ {
+		if i >= len(setting) {
+			return y, nil, 0, &ParseError{Pos: i, EOF: true, Err: ErrSaltTooShort}
+		}
+		if setting[i] != yescryptPrefix[i] {
+			return y, nil, 0, &ParseError{Pos: i, Char: setting[i], Err: ErrInvalidSalt}
+		}
+	}
+
+	// at records where each parameter starts, for error positions.
+	var at [5]int
+	var flavor, logN uint32
+	pos := len(yescryptPrefix)
+	for i, f := range []struct {
+		v   *uint32
+		min uint32
+	}{{&flavor, 0}, {&logN, 1}, {&y.r, 1}} {
+		at[i] = pos
+		if *f.v, pos, err = decodeYescryptUint32(setting, pos, f.min); err != nil {
+			return y, nil, 0, err
+		}
+	}
+	y.p = 1
+	at[3], at[4] = at[2], at[2]
+	if pos < len(setting) && setting[pos] != '$' {
+		var have uint32
+		haveAt := pos
+		if have, pos, err = decodeYescryptUint32(setting, pos, 1); err != nil {
+			return y, nil, 0, err
+		}
+		if have&^3 != 0 {
+			// A hash upgrade count or a ROM, neither usable here.
+			return y, nil, 0, &ParseError{Pos: haveAt, Char: setting[haveAt], Err: ErrInvalidCost}
+		}
+		if have&1 != 0 {
+			at[3] = pos
+			if y.p, pos, err = decodeYescryptUint32(setting, pos, 2); err != nil {
+				return y, nil, 0, err
+			}
+		}
+		if have&2 != 0 {
+			at[4] = pos
+			if y.t, pos, err = decodeYescryptUint32(setting, pos, 1); err != nil {
+				return y, nil, 0, err
+			}
+		}
+	}
+
+	bad := -1
+	switch flavor {
+	case 0, yescryptWORM:
+		y.flags = flavor
+	case (yescryptDefaults-yescryptRW)>>2 + yescryptRW:
+		y.flags = yescryptDefaults
+	default:
+		bad = 0
+	}
+	y.logN = uint(logN)
+	switch {
+	case bad >= 0:
+	case logN < 1 || logN > 31:
+		bad = 1
+	case uint64(y.r)<<logN > yescryptMaxMemory/128:
+		bad = 2
+	case uint64(y.r)*uint64(y.p) >= 1<<30 || y.memory() > yescryptMaxMemory:
+		bad = 3
+	case y.flags&yescryptRW != 0 && uint64(1)<<logN/uint64(y.p) <= 1:
+		bad = 3
+	case y.flags == 0 && y.t != 0, y.t > yescryptMaxTime:
+		bad = 4
+	}
+	if bad >= 0 {
+		return y, nil, 0, &ParseError{Pos: at[bad], Char: setting[at[bad]], Err: ErrInvalidCost}
+	}
+
+	if pos == len(setting) {
+		return y, nil, 0, &ParseError{Pos: pos, EOF: true, Err: ErrSaltTooShort}
+	}
+	if setting[pos] != '$' {
+		return y, nil, 0, &ParseError{Pos: pos, Char: setting[pos], Err: ErrInvalidCost}
+	}
+	pos++
+	end = len(setting)
+	if i := strings.LastIndexByte(setting[pos:], '$'); i >= 0 {
+		end = pos + i
+	}
+	if salt, err = decodeYescryptSalt(setting[pos:end], pos); err != nil {
+		return y, nil, 0, err
+	}
+	return y, salt, end, nil
+}
+
+// decodeYescryptUint32 decodes the variable-length number at setting[pos:]
+// as libxcrypt's decode64_uint32 does: the first character gives the
+// length and the high bits, the rest 6 bits each. It returns the number
+// plus min and the offset after it.
+func decodeYescryptUint32(setting string, pos int, min uint32) (uint32, int, error) {
+	digit := func() (uint32, error) {
+		if pos >= len(setting) {
+			return 0, &ParseError{Pos: pos, EOF: true, Err: ErrSaltTooShort}
+		}
+		c := strings.IndexByte(itoa64, setting[pos])
+		if c < 0 {
+			return 0, &ParseError{Pos: pos, Char: setting[pos], Err: ErrInvalidCost}
+		}
+		pos++
+		return uint32(c), nil
+	}
+	c, err := digit()
+	if err != nil {
+		return 0, pos, err
+	}
+	start, end, chars, shift := uint32(0), uint32(47), 1, 0
+	v := min
+	for c > end {
+		v += (end + 1 - start) << shift
+		start = end + 1
+		end = start + (62-end)/2
+		chars++
+		shift += 6
+	}
+	v += (c - start) << shift
+	for ; chars > 1; chars-- {
+		if c, err = digit(); err != nil {
+			return 0, pos, err
+		}
+		shift -= 6
+		v += c << shift
+	}
+	return v, pos, nil
+}
+
+// appendYescryptUint32 appends the variable-length encoding of v, which
+// must be at least min, that decodeYescryptUint32 reads.
+func appendYescryptUint32(dst []byte, v, min uint32) []byte {
+	v -= min
+	start, end, chars, shift := uint32(0), uint32(47), 1, 0
+	for v >= (end+1-start)<<shift {
+		v -= (end + 1 - start) << shift
+		start = end + 1
+		end = start + (62-end)/2
+		chars++
+		shift += 6
+	}
+	dst = append(dst, itoa64[start+v>>shift])
+	for ; chars > 1; chars-- {
+		shift -= 6
+		dst = append(dst, itoa64[v>>shift&63])
+	}
+	return dst
+}
+
+// decodeYescryptSalt decodes an encoded salt: each 4 characters, 6 bits
+// each least significant first, give 3 bytes, and a trailing 2 or 3 give 1
+// or 2 with the leftover bits zero. pos is the offset of s in the setting.
+func decodeYescryptSalt(s string, pos int) ([]byte, error) {
+	salt := make([]byte, 0, len(s)*3/4)
+	for len(s) > 0 {
+		n := min(len(s), 4)
+		var v uint32
+		for i := 0; i < n; i++ {
+			c := strings.IndexByte(itoa64, s[i])
+			if c < 0 {
+				return nil, &ParseError{Pos: pos + i, Char: s[i], Err: ErrInvalidSalt}
+			}
+			v |= uint32(c) << (6 * i)
+		}
+		nb := 6 * n / 8
+		if nb == 0 || v>>(8*nb) != 0 || len(salt)+nb > yescryptSaltMax {
+			return nil, &ParseError{Pos: pos + n - 1, Char: s[n-1], Err: ErrInvalidSalt}
+		}
+		for i := 0; i < nb; i++ {
+			salt = append(salt, byte(v>>(8*i)))
+		}
+		s = s[n:]
+		pos += n
+	}
+	return salt, nil
+}
+
+// appendYescrypt64 appends the encoding of src to dst: each 3 bytes,
+// little-endian, become 4 characters, and a trailing 1 or 2 bytes become 2
+// or 3.
+func appendYescrypt64(dst, src []byte) []byte {
+	for len(src) > 0 {
+		n := min(len(src), 3)
+		var v uint32
+		for i := 0; i < n; i++ {
+			v |= uint32(src[i]) << (8 * i)
+		}
+		dst = appendA64(dst, v, (8*n+5)/6)
+		src = src[n:]
+	}
+	return dst
+}
+
+// yescrypt returns the 32-byte yescrypt hash of passwd. Large read-write
+// settings first hash the password with N/64, as yescrypt_kdf does.
+func yescrypt(passwd, salt []byte, y *yescryptParams) [32]byte {
+	n := uint64(1) << y.logN
+	if y.flags&yescryptRW != 0 && n/uint64(y.p) >= 0x100 && n/uint64(y.p)*uint64(y.r) >= 0x20000 {
+		pre := *y
+		pre.flags |= yescryptPrehash
+		pre.logN -= 6
+		pre.t = 0
+		dk := yescryptBody(passwd, salt, &pre)
+		passwd = dk[:]
+	}
+	return yescryptBody(passwd, salt, y)
+}
+
+// yescryptBody is yescrypt_kdf_body from the reference implementation.
+func yescryptBody(passwd, salt []byte, y *yescryptParams) [32]byte {
+	n := uint64(1) << y.logN
+	r, p := int(y.r), int(y.p)
+	s := 32 * r
+
+	// For every flavor but classic scrypt, passwd becomes a 32-byte
+	// buffer that smix may update and that keys the final PBKDF2.
+	var key [32]byte
+	if y.flags != 0 {
+		name := "yescrypt-prehash"
+		if y.flags&yescryptPrehash == 0 {
+			name = name[:8]
+		}
+		key = hmacSHA256([]byte(name), passwd)
+		passwd = key[:]
+	}
+
+	b := make([]uint32, s*p)
+	buf := pbkdf2SHA256(passwd, salt, 4*len(b))
+	for i := range b {
+		b[i] = binary.LittleEndian.Uint32(buf[4*i:])
+	}
+	if y.flags != 0 {
+		copy(key[:], buf)
+	}
+
+	v := make([]uint32, uint64(s)*n)
+	xy := make([]uint32, 2*s)
+	if p == 1 || y.flags&yescryptRW != 0 {
+		smix(b, r, n, p, y.t, y.flags, v, xy, passwd)
+	} else {
+		for i := 0; i < p; i++ {
+			smix(b[s*i:s*(i+1)], r, n, 1, y.t, y.flags, v, xy, nil)
+		}
+	}
+
+	for i, w := range b {
+		binary.LittleEndian.PutUint32(buf[4*i:], w)
+	}
+	var dk [32]byte
+	copy(dk[:], pbkdf2SHA256(passwd, buf, len(dk)))
+
+	// The final steps are those of SCRAM: the hash is the StoredKey
+	// for a ClientKey derived from dk.
+	if y.flags != 0 && y.flags&yescryptPrehash == 0 {
+		clientKey := hmacSHA256(dk[:], []byte("Client Key"))
+		dk = sha256.Sum256(clientKey[:])
+	}
+	return dk
+}
+
+// hmacSHA256 returns HMAC-SHA256 of msg under key.
+func hmacSHA256(key, msg []byte) [32]byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(msg)
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// pbkdf2SHA256 returns n bytes of PBKDF2-HMAC-SHA256 with one iteration,
+// the only count yescrypt uses.
+func pbkdf2SHA256(passwd, salt []byte, n int) []byte {
+	h := hmac.New(sha256.New, passwd)
+	out := make([]byte, 0, n+sha256.Size)
+	for i := uint32(1); len(out) < n; i++ {
+		h.Reset()
+		h.Write(salt)
+		h.Write(binary.BigEndian.AppendUint32(nil, i))
+		out = h.Sum(out)
+	}
+	return out[:n]
+}
+
+// The reference implementation, and so the hash, keeps each 64-byte
+// sub-block in the order used by SIMD Salsa20 code: word i of a shuffled
+// sub-block is word i*5%16 of the natural one. pwxform and the S-boxes
+// see the shuffled words.
+
+// smix runs SMix over the p blocks of b, using v for the N blocks of
+// memory and xy as scratch. For the read-write flavor passwd is the
+// 32-byte buffer that the first lane mixes its last sub-block into.
+func smix(b []uint32, r int, n uint64, p int, t, flags uint32, v, xy []uint32, passwd []byte) {
+	s := 32 * r
+	nchunk := n / uint64(p)
+	nloopAll := nchunk
+	if flags&yescryptRW != 0 {
+		if t <= 1 {
+			if t != 0 {
+				nloopAll *= 2
+			}
+			nloopAll = (nloopAll + 2) / 3
+		} else {
+			nloopAll *= uint64(t - 1)
+		}
+	} else if t != 0 {
+		if t == 1 {
+			nloopAll += (nloopAll + 1) / 2
+		}
+		nloopAll *= uint64(t)
+	}
+	var nloopRW uint64
+	if flags&yescryptRW != 0 {
+		nloopRW = nloopAll / uint64(p)
+	}
+	nchunk &^= 1
+	nloopAll = (nloopAll + 1) &^ 1
+	nloopRW = (nloopRW + 1) &^ 1
+
+	var ctx []pwxformCtx
+	if flags&yescryptRW != 0 {
+		ctx = make([]pwxformCtx, p)
+	}
+	var vchunk uint64
+	for i := 0; i < p; i++ {
+		np := nchunk
+		if i == p-1 {
+			np = n - vchunk
+		}
+		bp := b[s*i : s*(i+1)]
+		vp := v[uint64(s)*vchunk:]
+		var c *pwxformCtx
+		if flags&yescryptRW != 0 {
+			c = &ctx[i]
+			sv := make([]uint32, sBytes/4)
+			smix1(bp, 1, sBytes/128, 0, sv, xy, nil)
+			c.init(sv)
+			if i == 0 {
+				var last [64]byte
+				for k, w := range bp[s-16:] {
+					binary.LittleEndian.PutUint32(last[4*k:], w)
+				}
+				sum := hmacSHA256(last[:], passwd)
+				copy(passwd, sum[:])
+			}
+		}
+		smix1(bp, r, np, flags, vp, xy, c)
+		smix2(bp, r, uint64(1)<<(bits.Len64(np)-1), nloopRW, flags, vp, xy, c)
+		vchunk += nchunk
+	}
+	for i := 0; i < p; i++ {
+		var c *pwxformCtx
+		if flags&yescryptRW != 0 {
+			c = &ctx[i]
+		}
+		smix2(b[s*i:s*(i+1)], r, n, nloopAll-nloopRW, flags&^yescryptRW, v, xy, c)
+	}
+}
+
+// smix1 fills v with n successive blocks from b and leaves the next in b.
+func smix1(b []uint32, r int, n uint64, flags uint32, v, xy []uint32, c *pwxformCtx) {
+	s := 32 * r
+	x, y := xy[:s], xy[s:2*s]
+	shuffle(x, b)
+	for i := uint64(0); i < n; i++ {
+		copy(v[i*uint64(s):], x)
+		if flags&yescryptRW != 0 && i > 1 {
+			j := wrap(integerify(x), i)
+			xorWords(x, v[j*uint64(s):])
+		}
+		blockmix(x, y, c)
+	}
+	unshuffle(b, x)
+}
+
+// smix2 mixes b with nloop pseudorandomly chosen blocks of the first n of
+// v, writing each back in read-write mode.
+func smix2(b []uint32, r int, n, nloop uint64, flags uint32, v, xy []uint32, c *pwxformCtx) {
+	s := 32 * r
+	x, y := xy[:s], xy[s:2*s]
+	shuffle(x, b)
+	for i := uint64(0); i < nloop; i++ {
+		j := integerify(x) & (n - 1)
+		vj := v[j*uint64(s) : (j+1)*uint64(s)]
+		xorWords(x, vj)
+		if flags&yescryptRW != 0 {
+			copy(vj, x)
+		}
+		blockmix(x, y, c)
+	}
+	unshuffle(b, x)
+}
+
+func shuffle(dst, src []uint32) {
+	for k := 0; k < len(dst); k += 16 {
+		for i := 0; i < 16; i++ {
+			dst[k+i] = src[k+i*5%16]
+		}
+	}
+}
+
+func unshuffle(dst, src []uint32) {
+	for k := 0; k < len(src); k += 16 {
+		for i := 0; i < 16; i++ {
+			dst[k+i*5%16] = src[k+i]
+		}
+	}
+}
+
+// integerify returns the first 64 bits of the last sub-block of x.
+func integerify(x []uint32) uint64 {
+	last := x[len(x)-16:]
+	return uint64(last[13])<<32 | uint64(last[0])
+}
+
+// wrap maps x into the i blocks written so far, favoring the most recent.
+func wrap(x, i uint64) uint64 {
+	n := uint64(1) << (bits.Len64(i) - 1)
+	return x&(n-1) + (i - n)
+}
+
+func xorWords(dst, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// blockmix is BlockMix_pwxform if c is set, and scrypt's BlockMix with
+// Salsa20/8 otherwise. y is scratch space the size of x.
+func blockmix(x, y []uint32, c *pwxformCtx) {
+	r1 := len(x) / 16
+	if c != nil {
+		var t [16]uint32
+		copy(t[:], x[(r1-1)*16:])
+		for i := 0; i < r1; i++ {
+			xorWords(t[:], x[i*16:])
+			c.pwxform(&t)
+			copy(x[i*16:], t[:])
+		}
+		salsa20(x[(r1-1)*16:], 2)
+		return
+	}
+
+	var t [16]uint32
+	copy(t[:], x[(r1-1)*16:])
+	for i := 0; i < r1; i++ {
+		xorWords(t[:], x[i*16:])
+		salsa20(t[:], 8)
+		copy(y[i*16:], t[:])
+	}
+	for i := 0; i < r1/2; i++ {
+		copy(x[i*16:(i+1)*16], y[2*i*16:])
+		copy(x[(i+r1/2)*16:(i+r1/2+1)*16], y[(2*i+1)*16:])
+	}
+}
+
+// salsa20 applies the Salsa20 core with the given number of rounds to the
+// shuffled sub-block b.
+func salsa20(b []uint32, rounds int) {
+	var x [16]uint32
+	for i := 0; i < 16; i++ {
+		x[i*5%16] = b[i]
+	}
+	r := bits.RotateLeft32
+	for i := 0; i < rounds; i += 2 {
+		x[4] ^= r(x[0]+x[12], 7)
+		x[8] ^= r(x[4]+x[0], 9)
+		x[12] ^= r(x[8]+x[4], 13)
+		x[0] ^= r(x[12]+x[8], 18)
+		x[9] ^= r(x[5]+x[1], 7)
+		x[13] ^= r(x[9]+x[5], 9)
+		x[1] ^= r(x[13]+x[9], 13)
+		x[5] ^= r(x[1]+x[13], 18)
+		x[14] ^= r(x[10]+x[6], 7)
+		x[2] ^= r(x[14]+x[10], 9)
+		x[6] ^= r(x[2]+x[14], 13)
+		x[10] ^= r(x[6]+x[2], 18)
+		x[3] ^= r(x[15]+x[11], 7)
+		x[7] ^= r(x[3]+x[15], 9)
+		x[11] ^= r(x[7]+x[3], 13)
+		x[15] ^= r(x[11]+x[7], 18)
+
+		x[1] ^= r(x[0]+x[3], 7)
+		x[2] ^= r(x[1]+x[0], 9)
+		x[3] ^= r(x[2]+x[1], 13)
+		x[0] ^= r(x[3]+x[2], 18)
+		x[6] ^= r(x[5]+x[4], 7)
+		x[7] ^= r(x[6]+x[5], 9)
+		x[4] ^= r(x[7]+x[6], 13)
+		x[5] ^= r(x[4]+x[7], 18)
+		x[11] ^= r(x[10]+x[9], 7)
+		x[8] ^= r(x[11]+x[10], 9)
+		x[9] ^= r(x[8]+x[11], 13)
+		x[10] ^= r(x[9]+x[8], 18)
+		x[12] ^= r(x[15]+x[14], 7)
+		x[13] ^= r(x[12]+x[15], 9)
+		x[14] ^= r(x[13]+x[12], 13)
+		x[15] ^= r(x[14]+x[13], 18)
+	}
+	for i := 0; i < 16; i++ {
+		b[i] += x[i*5%16]
+	}
+}
+
+// pwxformCtx is the S-box state of one lane of the read-write flavor:
+// three 4 KiB S-boxes in s, which rotate roles after each pwxform, and
+// the next word of S2 to write.
+type pwxformCtx struct {
+	s          [3 * sWords]uint64
+	s0, s1, s2 int
+	w          int
+}
+
+// init sets up the S-boxes from the words smix1 filled.
+func (c *pwxformCtx) init(sv []uint32) {
+	for i := range c.s {
+		c.s[i] = uint64(sv[2*i]) | uint64(sv[2*i+1])<<32
+	}
+	c.s2, c.s1, c.s0 = 0, sWords, 2*sWords
+	c.w = 0
+}
+
+// pwxform transforms a 64-byte block, treated as pwxGather lanes of
+// pwxSimple 64-bit words, and records intermediate rounds in S2.
+func (c *pwxformCtx) pwxform(x *[16]uint32) {
+	s0, s1, s2 := c.s[c.s0:c.s0+sWords], c.s[c.s1:c.s1+sWords], c.s[c.s2:c.s2+sWords]
+	w := c.w
+	for i := 0; i < pwxRounds; i++ {
+		for j := 0; j < pwxGather; j++ {
+			lane := x[4*j : 4*j+4]
+			p0 := int(lane[0]&sMask) / 8
+			p1 := int(lane[1]&sMask) / 8
+			for k := 0; k < pwxSimple; k++ {
+				v := uint64(lane[2*k+1])*uint64(lane[2*k]) + s0[p0+k]
+				v ^= s1[p1+k]
+				lane[2*k], lane[2*k+1] = uint32(v), uint32(v>>32)
+			}
+			if i != 0 && i != pwxRounds-1 {
+				for k := 0; k < pwxSimple; k++ {
+					s2[w] = uint64(lane[2*k]) | uint64(lane[2*k+1])<<32
+					w++
+				}
+			}
+		}
+	}
+	c.s0, c.s1, c.s2 = c.s2, c.s0, c.s1
+	c.w = w & (sWords - 1)
+}
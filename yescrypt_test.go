@@ -0,0 +1,146 @@
+package descrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// yescrypt vectors produced by libxcrypt.
+var yescryptTests = []struct {
+	password string
+	hash     string
+}{
+	{"password", "$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC"},
+	{"", "$y$j9T$F5Jx5fExrKuPp53xLKQ..1$5P1uc1zvKhieqEtKttbwCQrTPXpY1cK9wEnTDKAqLD8"},
+	{"password", "$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9"},
+	{"password", "$y$j5T$abcd$8ZzX6QN3IVjVm1ENlfzlpUJr1eRsUqVe5sp0bVX8fE6"},
+	{"password", "$y$j6.0/1$xyz0$BuZrb49P/WYCxygTbnqvMjRLQ4TvbPo9NxfwSpUZKx8"},
+	{"password", "$y$jA.$$6BZtpvky7FK0Va4r.QFJu0ilqibw/7WZhLnKFENTg41"},
+	{"password", "$y$j9T0..$abc.$1PuR2xXKUtHxV7x.kFvTPnG7tDT/.UYRFmRdjcHVVJ2"},
+	{"password", "$y$/6./0$xyz0$XNMo7LmYDN1nvne5ga8EfpGfaJNYI5EC9GHace/Zlg1"},
+	{"password", "$y$/5.$abcdefgh$zKI30LlmcJlQsAJVY515GkcTt4xkMTZa31l/cU1b594"},
+	{"password", "$y$.5.$abcdefgh$jcA6geWFuuslgXN8V3wSHMehNmwQ2KvHpGnt9JVVof6"},
+	{"", "$y$.6...$xyz0$bhMeE8EolaASM5oSCx0w6r8QhZfVfJBGAvo4DWOYBvC"},
+}
+
+func TestYescryptHash(t *testing.T) {
+	for _, tc := range yescryptTests {
+		got, err := YescryptHash(tc.password, tc.hash)
+		if err != nil {
+			t.Fatalf("YescryptHash(%q) error = %v", tc.hash, err)
+		}
+		if got != tc.hash {
+			t.Errorf("YescryptHash(%q) = %v, want %v", tc.password, got, tc.hash)
+		}
+		if err := YescryptPasswordVerify(tc.password, tc.hash); err != nil {
+			t.Errorf("YescryptPasswordVerify(%q, %q) error = %v", tc.password, tc.hash, err)
+		}
+		if err := YescryptPasswordVerify(tc.password, "{CRYPT}"+tc.hash); err != nil {
+			t.Errorf("YescryptPasswordVerify() error = %v with {CRYPT} prefix", err)
+		}
+		if err := YescryptPasswordVerify(tc.password+"x", tc.hash); !errors.Is(err, ErrMismatch) {
+			t.Errorf("YescryptPasswordVerify(%q, %q) error = %v, want ErrMismatch", tc.password+"x", tc.hash, err)
+		}
+	}
+
+	for setting, want := range map[string]string{
+		"$y$j5.$abcdefgh":     "$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9",
+		"$y$j5.$abcdefgh$":    "$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9",
+		"$y$j5.$abcdefgh$xyz": "$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9",
+	} {
+		if got, err := YescryptHash("password", setting); got != want {
+			t.Errorf("YescryptHash(%q) = %v, %v, want %v", setting, got, err, want)
+		}
+	}
+}
+
+func TestYescryptErrors(t *testing.T) {
+	settings := []struct {
+		setting string
+		want    error
+	}{
+		{"$y", ErrSaltTooShort},
+		{"$y$j9T", ErrSaltTooShort},
+		{"$x$j9T$abc", ErrInvalidSalt},
+		{"$y$i9T$abc.", ErrInvalidCost},   // unsupported flavor
+		{"$y$j.T..$abc.", ErrInvalidCost}, // N/p = 1
+		{"$y$jzT$abc.", ErrInvalidCost},   // N too large
+		{"$y$j9T/$abc.", ErrInvalidCost},  // missing t
+		{"$y$j9T1.$abc.", ErrInvalidCost}, // ROM
+		{"$y$.6./.$abc.", ErrInvalidCost}, // t for classic scrypt
+		{"$y$j9T$abc!", ErrInvalidSalt},
+		{"$y$j9T$a", ErrInvalidSalt},  // less than a byte
+		{"$y$j9T$az", ErrInvalidSalt}, // leftover bits set
+	}
+	for _, tc := range settings {
+		if _, err := YescryptHash("password", tc.setting); !errors.Is(err, tc.want) {
+			t.Errorf("YescryptHash(%q) error = %v, want %v", tc.setting, err, tc.want)
+		}
+	}
+
+	testCases := []struct {
+		hash string
+		pos  int
+		char byte
+	}{
+		{"", 0, 0},
+		{"$y$j5.$abcdefgh", 15, 0},
+		{"$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK", 58, 0},
+		{"$y$j5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9x", 59, 'x'},
+		{"$y$j5.$abcdefgh$1mllMpggQqQfn.sNcg_e4.P9SZxOfMY1LK4cImmYSK9", 34, '_'},
+		{"$y$j5.$abcdefg!$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9", 14, '!'},
+		{"{CRYPT}$y$i5.$abcdefgh$1mllMpggQqQfn.sNcgke4.P9SZxOfMY1LK4cImmYSK9", 10, 'i'},
+	}
+	for _, tc := range testCases {
+		err := YescryptPasswordVerify("password", tc.hash)
+		var pe *ParseError
+		if !errors.Is(err, ErrMalformedHash) || !errors.As(err, &pe) {
+			t.Errorf("YescryptPasswordVerify(%q) error = %v, want ErrMalformedHash", tc.hash, err)
+			continue
+		}
+		if pe.Pos != tc.pos || pe.Char != tc.char {
+			t.Errorf("YescryptPasswordVerify(%q) error at %d %q, want %d %q", tc.hash, pe.Pos, pe.Char, tc.pos, tc.char)
+		}
+	}
+}
+
+func TestYescryptLimits(t *testing.T) {
+	// libxcrypt's highest cost fits under the limits.
+	setting, err := GenerateSalt("yescrypt", yescryptMaxCost, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := parseYescryptSetting(setting); err != nil {
+		t.Errorf("parseYescryptSetting(%q) error = %v", setting, err)
+	}
+
+	// A huge p under the write-once and classic flavors would have B
+	// alone take gigabytes, and a huge t would make hashing take forever.
+	hugeT := string(appendYescryptUint32([]byte("$y$j9T"), 2, 1))
+	hugeT = string(appendYescryptUint32([]byte(hugeT), 1000, 1))
+	hashes := []string{
+		"$y$jGT$$" + strings.Repeat(".", yescryptHashLen), // 2 GiB for V
+		string(appendYescryptUint32(appendYescryptUint32([]byte("$y$j9T"), 2, 1), yescryptMaxTime+1, 1)) + "$$" + strings.Repeat(".", yescryptHashLen),
+		"$y$/...zg....$$" + strings.Repeat(".", yescryptHashLen),
+		"$y$....zg....$$" + strings.Repeat(".", yescryptHashLen),
+		hugeT + "$$" + strings.Repeat(".", yescryptHashLen),
+	}
+	for _, hash := range hashes {
+		if err := YescryptPasswordVerify("pw", hash); !errors.Is(err, ErrMalformedHash) {
+			t.Errorf("YescryptPasswordVerify(%q) error = %v, want ErrMalformedHash", hash, err)
+		}
+		if _, err := Identify(hash); !errors.Is(err, ErrMalformedHash) {
+			t.Errorf("Identify(%q) error = %v, want ErrMalformedHash", hash, err)
+		}
+		if _, err := YescryptHash("pw", hash); !errors.Is(err, ErrInvalidCost) {
+			t.Errorf("YescryptHash(%q) error = %v, want ErrInvalidCost", hash, err)
+		}
+	}
+}
+
+func BenchmarkYescryptHash(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		YescryptHash("password", "$y$j9T$F5Jx5fExrKuPp53xLKQ..1")
+	}
+}